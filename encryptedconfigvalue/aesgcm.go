@@ -54,10 +54,22 @@ func NewAESGCMEncrypter() Encrypter {
 }
 
 func (a *aesGCMEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	return a.encryptWithContext(input, key, nil)
+}
+
+func (a *aesGCMEncrypter) encryptWithContext(input string, key KeyWithType, context []byte) (EncryptedValue, error) {
+	return a.encrypt(input, key, context, nil)
+}
+
+func (a *aesGCMEncrypter) encryptWithPolicy(input string, key KeyWithType, policy []byte) (EncryptedValue, error) {
+	return a.encrypt(input, key, nil, policy)
+}
+
+func (a *aesGCMEncrypter) encrypt(input string, key KeyWithType, context, policy []byte) (EncryptedValue, error) {
 	aesGCMCipher := (*encryption.AESGCMCipher)(a)
 
 	// encryptedBytes consists of [nonce + encrypted + tag]
-	encryptedBytes, err := aesGCMCipher.Encrypt([]byte(input), key.Key)
+	encryptedBytes, err := aesGCMCipher.EncryptWithAdditionalData([]byte(input), additionalData(context, policy), key.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -65,9 +77,10 @@ func (a *aesGCMEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue
 	nonce, encrypted, tag := aesGCMCipher.Parts(encryptedBytes)
 
 	return &aesGCMEncryptedValue{
-		encrypted: encrypted,
-		nonce:     nonce,
-		tag:       tag,
+		encrypted:    encrypted,
+		nonce:        nonce,
+		tag:          tag,
+		policyConfig: policy,
 	}, nil
 }
 
@@ -75,14 +88,24 @@ type aesGCMEncryptedValue struct {
 	encrypted []byte
 	nonce     []byte
 	tag       []byte
+	keyHint   string
+	// policyConfig is the canonical JSON representation of the authenticated policy of the value, or nil if the value
+	// does not have a policy.
+	policyConfig []byte
+	parsedJSON
 }
 
+// aesGCMEncryptedValueJSON is the JSON representation of an AES-GCM encrypted value. The GCM tag is always written
+// to its own "tag" field. When reading, the "tag" field may be omitted, in which case the tag must be appended to the
+// ciphertext (the layout used by libraries that emit [ciphertext+tag] as a single field).
 type aesGCMEncryptedValueJSON struct {
-	Type       string `json:"type"`
-	Mode       string `json:"mode"`
-	Ciphertext string `json:"ciphertext"`
-	IV         string `json:"iv"`
-	Tag        string `json:"tag"`
+	Type       string          `json:"type"`
+	Mode       string          `json:"mode"`
+	Ciphertext string          `json:"ciphertext"`
+	IV         string          `json:"iv"`
+	Tag        string          `json:"tag"`
+	KeyHint    string          `json:"key_hint,omitempty"`
+	Policy     json.RawMessage `json:"policy,omitempty"`
 }
 
 const gcmMode = "GCM"
@@ -94,6 +117,8 @@ func (ev aesGCMEncryptedValue) MarshalJSON() ([]byte, error) {
 		Ciphertext: base64.StdEncoding.EncodeToString(ev.encrypted),
 		IV:         base64.StdEncoding.EncodeToString(ev.nonce),
 		Tag:        base64.StdEncoding.EncodeToString(ev.tag),
+		KeyHint:    ev.keyHint,
+		Policy:     ev.policyConfig,
 	})
 }
 
@@ -118,21 +143,68 @@ func (ev *aesGCMEncryptedValue) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
+	if evJSON.Tag == "" {
+		// tag is not stored separately: it is appended to the ciphertext
+		if len(encrypted) < aesGCMDefaultTagSizeBytes {
+			return fmt.Errorf("ciphertext must contain the appended tag when no tag is specified")
+		}
+		encrypted, tag = encrypted[:len(encrypted)-aesGCMDefaultTagSizeBytes], encrypted[len(encrypted)-aesGCMDefaultTagSizeBytes:]
+	}
+	var policy []byte
+	if evJSON.Policy != nil {
+		if policy, err = canonicalPolicy(evJSON.Policy); err != nil {
+			return err
+		}
+	}
 	*ev = aesGCMEncryptedValue{
-		encrypted: encrypted,
-		nonce:     nonce,
-		tag:       tag,
+		encrypted:    encrypted,
+		nonce:        nonce,
+		tag:          tag,
+		keyHint:      evJSON.KeyHint,
+		policyConfig: policy,
 	}
 	return nil
 }
 
 func (ev *aesGCMEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	if ev.policyConfig != nil {
+		return "", errPolicyNotEnforced
+	}
+	return ev.decryptWithContext(key, nil)
+}
+
+func (ev *aesGCMEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
+func (ev *aesGCMEncryptedValue) decryptWithContext(key KeyWithType, context []byte) (string, error) {
 	aesGCMCipher := encryption.AESGCMCipherWithNonceAndTagSize(len(ev.nonce), len(ev.tag))
 	encrypted := append(ev.nonce, append(ev.encrypted, ev.tag...)...)
-	decrypted, err := aesGCMCipher.Decrypt(encrypted, key.Key)
+	decrypted, err := aesGCMCipher.DecryptWithAdditionalData(encrypted, additionalData(context, ev.policyConfig), key.Key)
 	return string(decrypted), err
 }
 
 func (ev *aesGCMEncryptedValue) ToSerializable() SerializedEncryptedValue {
-	return encryptedValToSerializable(ev)
+	return toSerializable(ev)
+}
+
+func (ev *aesGCMEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return encryptedValToSerializableBytes(ev)
+}
+
+func (ev *aesGCMEncryptedValue) KeyHint() (string, bool) {
+	return ev.keyHint, ev.keyHint != ""
+}
+
+func (ev *aesGCMEncryptedValue) setKeyHint(hint string) {
+	ev.keyHint = hint
+	ev.parsedJSON = parsedJSON{}
+}
+
+func (ev *aesGCMEncryptedValue) policy() []byte {
+	return ev.policyConfig
+}
+
+func (ev *aesGCMEncryptedValue) algorithm() AlgorithmType {
+	return AES
 }
@@ -122,3 +122,45 @@ func TestAESDecryptUsingStoredKey(t *testing.T) {
 		assert.Equal(t, string(currCase.plaintext), gotPlaintext, "Case %d: %s", i, currCase.name)
 	}
 }
+
+func TestAESJSONTagLayouts(t *testing.T) {
+	const wantJSON = `{"type":"AES","mode":"GCM","ciphertext":"hGYI+23l1vDMjQ==","iv":"DbEqWuhTvB9x1wkA","tag":"wmCU2C8xTtWc4+er22oXLA=="}`
+	aesKeyBytes, err := base64.StdEncoding.DecodeString("0JlMK+vn1T8+d43NRp49xi35lA/NQVSTeowTw4iLw5M=")
+	require.NoError(t, err)
+	aesKey := AESKeyFromBytes(aesKeyBytes)
+
+	for i, currCase := range []struct {
+		name string
+		json string
+	}{
+		{
+			name: "separate tag",
+			json: wantJSON,
+		},
+		{
+			name: "tag appended to ciphertext",
+			json: `{"type":"AES","mode":"GCM","ciphertext":"hGYI+23l1vDMjcJglNgvMU7VnOPnq9tqFyw=","iv":"DbEqWuhTvB9x1wkA"}`,
+		},
+		{
+			name: "tag appended to ciphertext with empty tag field",
+			json: `{"type":"AES","mode":"GCM","ciphertext":"hGYI+23l1vDMjcJglNgvMU7VnOPnq9tqFyw=","iv":"DbEqWuhTvB9x1wkA","tag":""}`,
+		},
+	} {
+		var ev aesGCMEncryptedValue
+		err := json.Unmarshal([]byte(currCase.json), &ev)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		decrypted, err := ev.Decrypt(aesKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, "test input", decrypted, "Case %d: %s", i, currCase.name)
+
+		// values are always written with a separate tag
+		marshaledJSON, err := json.Marshal(ev)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, wantJSON, string(marshaledJSON), "Case %d: %s", i, currCase.name)
+	}
+
+	var ev aesGCMEncryptedValue
+	err = json.Unmarshal([]byte(`{"type":"AES","mode":"GCM","ciphertext":"hGYI+23l","iv":"DbEqWuhTvB9x1wkA"}`), &ev)
+	assert.EqualError(t, err, "ciphertext must contain the appended tag when no tag is specified")
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+const (
+	gcmSegmentedMode = "GCM-SEGMENTED"
+	hkdfSHA256KDF    = "HKDF-SHA-256"
+)
+
+type aesGCMSegmentedEncrypter encryption.AESGCMSegmentedCipher
+
+// NewAESGCMSegmentedEncrypter returns an encrypter that encrypts values using AES-GCM in segments of the specified size
+// (in bytes), where every segment is encrypted using a separate key derived from the AES key using HKDF-SHA-256. This
+// allows values that are larger than the amount of data that can be safely encrypted using a single AES-GCM key and
+// nonce to be encrypted. The returned EncryptedValue will be serialized in the new format of "AES:<base64-encoded-JSON>",
+// where the JSON is the JSON representation of the aesGCMSegmentedEncryptedValueJSON struct. Returns an error if the
+// segment size is not positive or is larger than encryption.AESGCMSegmentedMaxSegmentSizeBytes.
+func NewAESGCMSegmentedEncrypter(segmentSizeBytes int) (Encrypter, error) {
+	segmentedCipher, err := encryption.AESGCMSegmentedCipherWithSegmentSize(segmentSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return (*aesGCMSegmentedEncrypter)(segmentedCipher), nil
+}
+
+func (a *aesGCMSegmentedEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	segmentedCipher := (*encryption.AESGCMSegmentedCipher)(a)
+
+	// encryptedBytes consists of [salt + segments]
+	encryptedBytes, err := segmentedCipher.Encrypt([]byte(input), key.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, segments := segmentedCipher.Parts(encryptedBytes)
+
+	return &aesGCMSegmentedEncryptedValue{
+		salt:        salt,
+		encrypted:   segments,
+		segmentSize: segmentedCipher.SegmentSize(),
+	}, nil
+}
+
+type aesGCMSegmentedEncryptedValue struct {
+	salt        []byte
+	encrypted   []byte
+	segmentSize int
+	keyHint     string
+	parsedJSON
+}
+
+type aesGCMSegmentedEncryptedValueJSON struct {
+	Type        string `json:"type"`
+	Mode        string `json:"mode"`
+	KDF         string `json:"kdf"`
+	SegmentSize int    `json:"segment-size"`
+	Salt        string `json:"salt"`
+	Ciphertext  string `json:"ciphertext"`
+	KeyHint     string `json:"key_hint,omitempty"`
+}
+
+func (ev aesGCMSegmentedEncryptedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(aesGCMSegmentedEncryptedValueJSON{
+		Type:        string(AES),
+		Mode:        gcmSegmentedMode,
+		KDF:         hkdfSHA256KDF,
+		SegmentSize: ev.segmentSize,
+		Salt:        base64.StdEncoding.EncodeToString(ev.salt),
+		Ciphertext:  base64.StdEncoding.EncodeToString(ev.encrypted),
+		KeyHint:     ev.keyHint,
+	})
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) UnmarshalJSON(data []byte) error {
+	var evJSON aesGCMSegmentedEncryptedValueJSON
+	if err := json.Unmarshal(data, &evJSON); err != nil {
+		return err
+	}
+	if evJSON.Mode != gcmSegmentedMode {
+		return fmt.Errorf("unsupported mode: expected %q, but was %q", gcmSegmentedMode, evJSON.Mode)
+	}
+	if evJSON.KDF != hkdfSHA256KDF {
+		return fmt.Errorf("unsupported key derivation function: only %q is supported, but was %q", hkdfSHA256KDF, evJSON.KDF)
+	}
+	if _, err := encryption.AESGCMSegmentedCipherWithSegmentSize(evJSON.SegmentSize); err != nil {
+		return err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(evJSON.Salt)
+	if err != nil {
+		return err
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(evJSON.Ciphertext)
+	if err != nil {
+		return err
+	}
+	*ev = aesGCMSegmentedEncryptedValue{
+		salt:        salt,
+		encrypted:   encrypted,
+		segmentSize: evJSON.SegmentSize,
+		keyHint:     evJSON.KeyHint,
+	}
+	return nil
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	segmentedCipher, err := encryption.AESGCMSegmentedCipherWithSegmentSize(ev.segmentSize)
+	if err != nil {
+		return "", err
+	}
+	encrypted := append(append([]byte{}, ev.salt...), ev.encrypted...)
+	decrypted, err := segmentedCipher.Decrypt(encrypted, key.Key)
+	return string(decrypted), err
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) ToSerializable() SerializedEncryptedValue {
+	return toSerializable(ev)
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return encryptedValToSerializableBytes(ev)
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) KeyHint() (string, bool) {
+	return ev.keyHint, ev.keyHint != ""
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) setKeyHint(hint string) {
+	ev.keyHint = hint
+	ev.parsedJSON = parsedJSON{}
+}
+
+func (ev *aesGCMSegmentedEncryptedValue) algorithm() AlgorithmType {
+	return AES
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCMSegmentedEncryptDecrypt(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name        string
+		segmentSize int
+		input       string
+	}{
+		{"empty input", 1024, ""},
+		{"single segment", 1024, "secret message"},
+		{"many segments", 7, strings.Repeat("secret message ", 100)},
+	} {
+		encrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(currCase.segmentSize)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		ev, err := encrypter.Encrypt(currCase.input, kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		decrypted, err := ev.Decrypt(kp.DecryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.input, decrypted, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestAESGCMSegmentedSerializedForm(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(4096)
+	require.NoError(t, err)
+	ev, err := encrypter.Encrypt("secret message", kp.EncryptionKey)
+	require.NoError(t, err)
+
+	jsonBytes, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(ev.ToSerializable()), "enc:"))
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonBytes, &fields))
+	assert.Equal(t, "AES", fields["type"])
+	assert.Equal(t, "GCM-SEGMENTED", fields["mode"])
+	assert.Equal(t, "HKDF-SHA-256", fields["kdf"])
+	assert.Equal(t, float64(4096), fields["segment-size"])
+	assert.Contains(t, fields, "salt")
+	assert.Contains(t, fields, "ciphertext")
+}
+
+func TestAESGCMSegmentedInvalidSegmentSize(t *testing.T) {
+	_, err := encryptedconfigvalue.NewEncryptedValue("enc:" + base64.StdEncoding.EncodeToString([]byte(
+		`{"type":"AES","mode":"GCM-SEGMENTED","kdf":"HKDF-SHA-256","segment-size":0,"salt":"","ciphertext":""}`)))
+	assert.EqualError(t, err, "segment size must be between 1 and 68719476704 bytes, was 0")
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// AuditRecord describes an encryption or decryption operation. It contains only metadata that is not secret: it never
+// contains plaintext or key material.
+type AuditRecord struct {
+	// Time is the time at which the operation completed.
+	Time time.Time
+	// Algorithm is the algorithm of the encrypted value. It is empty if the value is in the legacy format or if
+	// encryption failed.
+	Algorithm AlgorithmType
+	// KeyFingerprint is the fingerprint of the key used for the operation in the form "sha256:<hex-digest>". The
+	// fingerprint of an RSA key is the fingerprint of its public key, so the fingerprints recorded when encrypting a
+	// value using the public key and decrypting it using the private key are the same.
+	KeyFingerprint string
+	// ValueFingerprint is the SHA-256 digest of the serialized form of the encrypted value in the form
+	// "sha256:<hex-digest>". It is empty if encryption failed.
+	ValueFingerprint string
+	// Failed is true if the operation failed.
+	Failed bool
+}
+
+// AuditSink records encryption and decryption operations, for example in an audit log. Implementations are
+// responsible for recording the identity of the caller, which is not known to this library.
+type AuditSink interface {
+	// RecordEncrypt records an encryption operation.
+	RecordEncrypt(record AuditRecord)
+	// RecordDecrypt records a decryption operation.
+	RecordDecrypt(record AuditRecord)
+}
+
+type auditEncrypter struct {
+	encrypter Encrypter
+	sink      AuditSink
+}
+
+// WithAuditSink returns an Encrypter that encrypts values using the provided encrypter and records every encryption
+// operation (including failed ones) in the provided sink.
+func WithAuditSink(encrypter Encrypter, sink AuditSink) Encrypter {
+	return &auditEncrypter{
+		encrypter: encrypter,
+		sink:      sink,
+	}
+}
+
+func (e *auditEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	ev, err := e.encrypter.Encrypt(input, key)
+	if err != nil {
+		e.sink.RecordEncrypt(AuditRecord{
+			Time:           time.Now(),
+			KeyFingerprint: keyFingerprint(key),
+			Failed:         true,
+		})
+		return nil, err
+	}
+	e.sink.RecordEncrypt(newAuditRecord(ev, key, false))
+	return ev, nil
+}
+
+// WithDecryptAuditSink returns an Option that configures a Decrypter to record every decryption operation (including
+// failed ones) in the provided sink. Values that are not decrypted because of a rate limit are not recorded.
+func WithDecryptAuditSink(sink AuditSink) Option {
+	return func(o *decrypterOptions) {
+		o.auditSink = sink
+	}
+}
+
+func newAuditRecord(ev EncryptedValue, key KeyWithType, failed bool) AuditRecord {
+	record := AuditRecord{
+		Time:           time.Now(),
+		KeyFingerprint: keyFingerprint(key),
+		Failed:         failed,
+	}
+	if algValue, ok := ev.(algorithmValue); ok {
+		record.Algorithm = algValue.algorithm()
+	}
+	if serialized, err := ev.ToSerializableBytes(); err == nil {
+		digest := sha256.Sum256(serialized)
+		record.ValueFingerprint = "sha256:" + hex.EncodeToString(digest[:])
+	}
+	return record
+}
+
+// aesKeyFingerprintDomain is prepended to the bytes of AES keys before they are hashed to compute their fingerprint, so
+// that the fingerprint of an AES key is not its plain SHA-256 digest.
+const aesKeyFingerprintDomain = "encrypted-config-value AES key fingerprint\x00"
+
+// keyFingerprint returns the fingerprint of the provided key, which is of the form "sha256:<hex-digest>". The
+// fingerprint of an RSA key is the SHA-256 digest of the PEM representation of its public key, and the fingerprint of
+// an AES key is the SHA-256 digest of its bytes prefixed with aesKeyFingerprintDomain. Returns an empty string for
+// keys of other types and for keys whose key material cannot be loaded.
+func keyFingerprint(key KeyWithType) string {
+	key, release, err := resolveKeyWithType(key)
+	if err != nil {
+		return ""
+	}
+	defer release()
+
+	var content []byte
+	switch k := key.Key.(type) {
+	default:
+		return ""
+	case *encryption.AESKey:
+		content = append([]byte(aesKeyFingerprintDomain), k.Bytes()...)
+		defer zeroBytes(content)
+	case *encryption.RSAPublicKey:
+		content = k.Bytes()
+	case *encryption.RSAPrivateKey:
+		content = (*encryption.RSAPublicKey)(&k.PublicKey).Bytes()
+	}
+	digest := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
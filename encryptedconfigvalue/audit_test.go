@@ -0,0 +1,65 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditSink struct {
+	encrypts []encryptedconfigvalue.AuditRecord
+	decrypts []encryptedconfigvalue.AuditRecord
+}
+
+func (s *recordingAuditSink) RecordEncrypt(record encryptedconfigvalue.AuditRecord) {
+	s.encrypts = append(s.encrypts, record)
+}
+
+func (s *recordingAuditSink) RecordDecrypt(record encryptedconfigvalue.AuditRecord) {
+	s.decrypts = append(s.decrypts, record)
+}
+
+func TestAuditSink(t *testing.T) {
+	for i, currAlg := range []encryptedconfigvalue.AlgorithmType{
+		encryptedconfigvalue.AES,
+		encryptedconfigvalue.RSA,
+	} {
+		kp, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		otherKP, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		sink := &recordingAuditSink{}
+
+		ev, err := encryptedconfigvalue.WithAuditSink(currAlg.Encrypter(), sink).Encrypt("secret", kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		_, err = encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, encryptedconfigvalue.WithDecryptAuditSink(sink)).Decrypt(ev)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		_, err = encryptedconfigvalue.NewDecrypter(otherKP.DecryptionKey, encryptedconfigvalue.WithDecryptAuditSink(sink)).Decrypt(ev)
+		require.Error(t, err, "Case %d: %s", i, currAlg)
+
+		require.Len(t, sink.encrypts, 1, "Case %d: %s", i, currAlg)
+		require.Len(t, sink.decrypts, 2, "Case %d: %s", i, currAlg)
+		encryptRecord, decryptRecord, failedRecord := sink.encrypts[0], sink.decrypts[0], sink.decrypts[1]
+
+		assert.Equal(t, currAlg, encryptRecord.Algorithm, "Case %d: %s", i, currAlg)
+		assert.False(t, encryptRecord.Failed, "Case %d: %s", i, currAlg)
+		assert.True(t, strings.HasPrefix(encryptRecord.KeyFingerprint, "sha256:"), "Case %d: %s", i, currAlg)
+		assert.True(t, strings.HasPrefix(encryptRecord.ValueFingerprint, "sha256:"), "Case %d: %s", i, currAlg)
+		assert.False(t, encryptRecord.Time.IsZero(), "Case %d: %s", i, currAlg)
+
+		// the encryption and decryption keys have the same fingerprint
+		assert.Equal(t, encryptRecord.KeyFingerprint, decryptRecord.KeyFingerprint, "Case %d: %s", i, currAlg)
+		assert.Equal(t, encryptRecord.ValueFingerprint, decryptRecord.ValueFingerprint, "Case %d: %s", i, currAlg)
+		assert.False(t, decryptRecord.Failed, "Case %d: %s", i, currAlg)
+
+		assert.NotEqual(t, encryptRecord.KeyFingerprint, failedRecord.KeyFingerprint, "Case %d: %s", i, currAlg)
+		assert.True(t, failedRecord.Failed, "Case %d: %s", i, currAlg)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Canonicalize returns the canonical form of the provided serialized encrypted value, which is the result of parsing
+// it using NewEncryptedValue and serializing the result using ToSerializable (or ToSerializableCBOR for values of the
+// form "encc:..."). Values produced by the encrypters in this library are already in canonical form. A value that is
+// not in canonical form (for example, one that uses different JSON field order, whitespace, field name casing, unknown
+// fields or non-canonical base64) decrypts to the same plaintext as its canonical form. Returns an error if the
+// provided value cannot be parsed.
+func Canonicalize(s string) (string, error) {
+	ev, err := NewEncryptedValue(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(s, encCBORPrefix) {
+		serialized, err := ToSerializableCBOR(ev)
+		return string(serialized), err
+	}
+	serialized, err := ev.ToSerializableBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(serialized), nil
+}
+
+// IsCanonical returns true if the provided serialized encrypted value is identical to its canonical form as returned
+// by Canonicalize. Returns an error if the provided value cannot be parsed.
+func IsCanonical(s string) (bool, error) {
+	canonical, err := Canonicalize(s)
+	if err != nil {
+		return false, err
+	}
+	return s == canonical, nil
+}
+
+// InnerJSON returns the JSON representation of the provided value. For a value that was parsed from a serialized form
+// of the form "enc:...", the returned JSON is exactly the JSON that was base64-encoded in that serialized form, even if
+// it is not in canonical form (for example, if it uses a different field order or contains unknown fields). For all
+// other values (such as values returned by an Encrypter or parsed from the form "encc:..."), the returned JSON is the
+// canonical JSON for the value, which is the content that is base64-encoded in its serialized form as returned by
+// ToSerializable. Returns an error if the provided value is in the legacy format, which has no JSON form.
+func InnerJSON(ev EncryptedValue) ([]byte, error) {
+	if _, ok := ev.(*legacyEncryptedValue); ok {
+		return nil, fmt.Errorf("values in the legacy format do not have a JSON form")
+	}
+	if parsed, ok := ev.(parsedJSONValue); ok && parsed.parsedJSONBytes() != nil {
+		return append([]byte(nil), parsed.parsedJSONBytes()...), nil
+	}
+	jsonBytes, err := json.Marshal(ev)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal for EncryptedValue of type %T failed: %v", ev, err)
+	}
+	return jsonBytes, nil
+}
+
+// parsedJSONValue is implemented by EncryptedValue implementations that retain the JSON from which they were parsed.
+type parsedJSONValue interface {
+	// parsedJSONBytes returns the JSON from which the value was parsed, or nil if the value was not parsed from JSON.
+	parsedJSONBytes() []byte
+	setParsedJSONBytes(data []byte)
+}
+
+// parsedJSON stores the JSON from which an EncryptedValue was parsed. It is embedded in the EncryptedValue
+// implementations that have a JSON form.
+type parsedJSON struct {
+	raw []byte
+}
+
+func (p *parsedJSON) parsedJSONBytes() []byte {
+	return p.raw
+}
+
+func (p *parsedJSON) setParsedJSONBytes(data []byte) {
+	p.raw = data
+}
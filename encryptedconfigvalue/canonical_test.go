@@ -0,0 +1,108 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCanonical(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	encode := func(content string) string {
+		return "enc:" + base64.StdEncoding.EncodeToString([]byte(content))
+	}
+
+	for i, currCase := range []struct {
+		name      string
+		input     string
+		canonical bool
+	}{
+		{
+			name:      "canonical AES value",
+			input:     string(testAESEncryptedVal),
+			canonical: true,
+		},
+		{
+			name:      "canonical RSA value",
+			input:     string(testRSAEncryptedVal),
+			canonical: true,
+		},
+		{
+			name:      "canonical legacy value",
+			input:     string(javaLegacyAESEncryptedVal),
+			canonical: true,
+		},
+		{
+			name:  "reordered JSON fields",
+			input: encode(`{"mode":"GCM","type":"AES","ciphertext":"M94kIyoa5+2Z","iv":"uAGqRlP9wizpdB0z","tag":"ACSuzDwTULomsjxpFMkYKA=="}`),
+		},
+		{
+			name:  "extra whitespace",
+			input: encode(`{"type": "AES", "mode": "GCM", "ciphertext": "M94kIyoa5+2Z", "iv": "uAGqRlP9wizpdB0z", "tag": "ACSuzDwTULomsjxpFMkYKA=="}`),
+		},
+		{
+			name:  "different field name casing",
+			input: encode(`{"TYPE":"AES","mode":"GCM","ciphertext":"M94kIyoa5+2Z","iv":"uAGqRlP9wizpdB0z","tag":"ACSuzDwTULomsjxpFMkYKA=="}`),
+		},
+		{
+			name:  "unknown field",
+			input: encode(`{"type":"AES","mode":"GCM","ciphertext":"M94kIyoa5+2Z","iv":"uAGqRlP9wizpdB0z","tag":"ACSuzDwTULomsjxpFMkYKA==","extra":1}`),
+		},
+		{
+			name:  "non-canonical base64 padding bits",
+			input: encode(`{"type":"AES","mode":"GCM","ciphertext":"M94kIyoa5+2Z","iv":"uAGqRlP9wizpdB0z","tag":"ACSuzDwTULomsjxpFMkYKB=="}`),
+		},
+	} {
+		canonical, err := encryptedconfigvalue.IsCanonical(currCase.input)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.canonical, canonical, "Case %d: %s", i, currCase.name)
+
+		// canonicalized values are canonical and decrypt to the same plaintext
+		canonicalized, err := encryptedconfigvalue.Canonicalize(currCase.input)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		canonical, err = encryptedconfigvalue.IsCanonical(canonicalized)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.True(t, canonical, "Case %d: %s", i, currCase.name)
+		if currCase.input != string(testRSAEncryptedVal) && currCase.input != string(javaLegacyAESEncryptedVal) {
+			assert.Equal(t, string(testAESEncryptedVal), canonicalized, "Case %d: %s", i, currCase.name)
+			decrypted, err := encryptedconfigvalue.MustNewEncryptedValue(canonicalized).Decrypt(key)
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, "plaintext", decrypted, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestIsCanonicalInvalid(t *testing.T) {
+	_, err := encryptedconfigvalue.IsCanonical("not-encrypted")
+	assert.EqualError(t, err, `encrypted value must be of the form "enc:..." or "encc:..."`)
+}
+
+func TestInnerJSON(t *testing.T) {
+	innerJSON, err := encryptedconfigvalue.InnerJSON(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"AES","mode":"GCM","ciphertext":"M94kIyoa5+2Z","iv":"uAGqRlP9wizpdB0z","tag":"ACSuzDwTULomsjxpFMkYKA=="}`, string(innerJSON))
+	assert.Equal(t, string(testAESEncryptedVal), "enc:"+base64.StdEncoding.EncodeToString(innerJSON))
+
+	// the JSON of a parsed value is returned exactly as it was stored
+	nonCanonicalJSON := `{ "mode": "GCM", "type": "AES", "extra": true, "ciphertext": "M94kIyoa5+2Z", "iv": "uAGqRlP9wizpdB0z", "tag": "ACSuzDwTULomsjxpFMkYKA==" }`
+	innerJSON, err = encryptedconfigvalue.InnerJSON(encryptedconfigvalue.MustNewEncryptedValue("enc:" + base64.StdEncoding.EncodeToString([]byte(nonCanonicalJSON))))
+	require.NoError(t, err)
+	assert.Equal(t, nonCanonicalJSON, string(innerJSON))
+
+	// the canonical JSON is returned for a value that was not parsed
+	ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt("plaintext", encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey))
+	require.NoError(t, err)
+	innerJSON, err = encryptedconfigvalue.InnerJSON(ev)
+	require.NoError(t, err)
+	assert.Equal(t, string(ev.ToSerializable()), "enc:"+base64.StdEncoding.EncodeToString(innerJSON))
+
+	_, err = encryptedconfigvalue.InnerJSON(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal))
+	assert.EqualError(t, err, "values in the legacy format do not have a JSON form")
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// This file implements the subset of CBOR (RFC 8949) that is used to serialize encrypted values: a single map whose
+// keys are text strings and whose values are unsigned integers, byte strings or text strings. Encoding follows the
+// core deterministic encoding requirements (definite lengths, shortest-form arguments and keys sorted by their encoded
+// bytes), and decoding rejects any input that is not encoded deterministically, so every value has exactly one CBOR
+// encoding.
+
+const (
+	cborMajorUint  = 0
+	cborMajorBytes = 2
+	cborMajorText  = 3
+	cborMajorMap   = 5
+)
+
+// cborMap is a map that can be encoded as CBOR. Values must be of type uint64, []byte or string.
+type cborMap map[string]interface{}
+
+// encode returns the deterministic CBOR encoding of the map.
+func (m cborMap) encode() ([]byte, error) {
+	type entry struct {
+		key   []byte
+		value []byte
+	}
+	entries := make([]entry, 0, len(m))
+	for k, v := range m {
+		var value []byte
+		switch v := v.(type) {
+		case uint64:
+			value = cborHeader(cborMajorUint, v)
+		case []byte:
+			value = append(cborHeader(cborMajorBytes, uint64(len(v))), v...)
+		case string:
+			value = append(cborHeader(cborMajorText, uint64(len(v))), v...)
+		default:
+			return nil, fmt.Errorf("unsupported CBOR value of type %T for key %q", v, k)
+		}
+		entries = append(entries, entry{
+			key:   append(cborHeader(cborMajorText, uint64(len(k))), k...),
+			value: value,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	out := cborHeader(cborMajorMap, uint64(len(entries)))
+	for _, e := range entries {
+		out = append(out, e.key...)
+		out = append(out, e.value...)
+	}
+	return out, nil
+}
+
+// cborHeader returns the shortest encoding of the initial byte and argument for an item of the provided major type.
+func cborHeader(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		out := []byte{major<<5 | 25, 0, 0}
+		binary.BigEndian.PutUint16(out[1:], uint16(arg))
+		return out
+	case arg <= 0xffffffff:
+		out := []byte{major<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(out[1:], uint32(arg))
+		return out
+	default:
+		out := []byte{major<<5 | 27, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(out[1:], arg)
+		return out
+	}
+}
+
+// decodeCBORMap decodes the provided deterministically encoded CBOR map. Returns an error if the input is not a single
+// map of the supported form or is not encoded deterministically.
+func decodeCBORMap(data []byte) (cborMap, error) {
+	dec := cborDecoder{data: data}
+	major, n, err := dec.header()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborMajorMap {
+		return nil, fmt.Errorf("invalid CBOR: expected a map, but was major type %d", major)
+	}
+
+	m := make(cborMap)
+	var prevKey []byte
+	for i := uint64(0); i < n; i++ {
+		keyStart := dec.offset
+		key, err := dec.text()
+		if err != nil {
+			return nil, err
+		}
+		encodedKey := data[keyStart:dec.offset]
+		if prevKey != nil && bytes.Compare(prevKey, encodedKey) >= 0 {
+			return nil, fmt.Errorf("invalid CBOR: map keys must be unique and sorted")
+		}
+		prevKey = encodedKey
+
+		major, arg, err := dec.header()
+		if err != nil {
+			return nil, err
+		}
+		switch major {
+		case cborMajorUint:
+			m[key] = arg
+		case cborMajorBytes:
+			b, err := dec.read(arg)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = append([]byte{}, b...)
+		case cborMajorText:
+			b, err := dec.read(arg)
+			if err != nil {
+				return nil, err
+			}
+			if !utf8.Valid(b) {
+				return nil, fmt.Errorf("invalid CBOR: text string is not valid UTF-8")
+			}
+			m[key] = string(b)
+		default:
+			return nil, fmt.Errorf("invalid CBOR: unsupported major type %d for key %q", major, key)
+		}
+	}
+	if dec.offset != len(data) {
+		return nil, fmt.Errorf("invalid CBOR: unexpected data after map")
+	}
+	return m, nil
+}
+
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+// header reads the initial byte and argument of the next item. Returns an error if the item uses an indefinite length
+// or if its argument is not encoded in the shortest form.
+func (d *cborDecoder) header() (byte, uint64, error) {
+	start := d.offset
+	b, err := d.read(1)
+	if err != nil {
+		return 0, 0, err
+	}
+	major, additional := b[0]>>5, b[0]&0x1f
+
+	var arg uint64
+	switch {
+	case additional < 24:
+		return major, uint64(additional), nil
+	case additional == 24:
+		argBytes, err := d.read(1)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(argBytes[0])
+	case additional == 25:
+		argBytes, err := d.read(2)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(binary.BigEndian.Uint16(argBytes))
+	case additional == 26:
+		argBytes, err := d.read(4)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = uint64(binary.BigEndian.Uint32(argBytes))
+	case additional == 27:
+		argBytes, err := d.read(8)
+		if err != nil {
+			return 0, 0, err
+		}
+		arg = binary.BigEndian.Uint64(argBytes)
+	default:
+		return 0, 0, fmt.Errorf("invalid CBOR: indefinite lengths and reserved values are not supported")
+	}
+	if !bytes.Equal(cborHeader(major, arg), d.data[start:d.offset]) {
+		return 0, 0, fmt.Errorf("invalid CBOR: argument is not encoded in the shortest form")
+	}
+	return major, arg, nil
+}
+
+// text reads the next item, which must be a text string.
+func (d *cborDecoder) text() (string, error) {
+	major, n, err := d.header()
+	if err != nil {
+		return "", err
+	}
+	if major != cborMajorText {
+		return "", fmt.Errorf("invalid CBOR: expected a text string, but was major type %d", major)
+	}
+	b, err := d.read(n)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("invalid CBOR: text string is not valid UTF-8")
+	}
+	return string(b), nil
+}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.offset) {
+		return nil, fmt.Errorf("invalid CBOR: unexpected end of data")
+	}
+	out := d.data[d.offset : d.offset+int(n)]
+	d.offset += int(n)
+	return out, nil
+}
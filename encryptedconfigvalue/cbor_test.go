@@ -0,0 +1,55 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBORMapEncode(t *testing.T) {
+	encoded, err := cborMap{
+		"type": "AES",
+		"iv":   []byte{1, 2, 3},
+		"n":    uint64(500),
+	}.encode()
+	require.NoError(t, err)
+	// keys are sorted by their encoded bytes, so shorter keys come first
+	assert.Equal(t, "a3"+"616e"+"1901f4"+"626976"+"43010203"+"6474797065"+"63414553", hex.EncodeToString(encoded))
+
+	decoded, err := decodeCBORMap(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, cborMap{
+		"type": "AES",
+		"iv":   []byte{1, 2, 3},
+		"n":    uint64(500),
+	}, decoded)
+}
+
+func TestDecodeCBORMapErrors(t *testing.T) {
+	for i, currCase := range []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"not a map", "63414553", "invalid CBOR: expected a map, but was major type 3"},
+		{"truncated", "a1616e", "invalid CBOR: unexpected end of data"},
+		{"trailing data", "a0" + "00", "invalid CBOR: unexpected data after map"},
+		{"non-minimal argument", "a1" + "616e" + "1805", "invalid CBOR: argument is not encoded in the shortest form"},
+		{"indefinite length", "bf" + "ff", "invalid CBOR: indefinite lengths and reserved values are not supported"},
+		{"unsorted keys", "a2" + "6474797065" + "00" + "616e" + "00", "invalid CBOR: map keys must be unique and sorted"},
+		{"duplicate keys", "a2" + "616e" + "00" + "616e" + "00", "invalid CBOR: map keys must be unique and sorted"},
+		{"non-text key", "a1" + "00" + "00", "invalid CBOR: expected a text string, but was major type 0"},
+		{"unsupported value", "a1" + "616e" + "80", `invalid CBOR: unsupported major type 4 for key "n"`},
+	} {
+		input, err := hex.DecodeString(currCase.input)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		_, err = decodeCBORMap(input)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+	}
+}
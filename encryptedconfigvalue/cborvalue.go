@@ -0,0 +1,119 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const encCBORPrefix = "encc:"
+
+// cborBinaryFields are the fields of the JSON representation of encrypted values that contain base64-encoded binary
+// data. These fields are stored as CBOR byte strings; all other string fields are stored as CBOR text strings.
+var cborBinaryFields = map[string]bool{
+	"ciphertext":  true,
+	"iv":          true,
+	"tag":         true,
+	"salt":        true,
+	"wrapped-key": true,
+}
+
+// ToSerializableCBOR returns the CBOR serialized form of the provided EncryptedValue, which is of the form
+// "encc:<base64-encoded-CBOR>". The CBOR content is a map with the same fields as the JSON content of the "enc:..."
+// form, except that binary fields (such as the ciphertext, IV and tag) are stored as CBOR byte strings rather than as
+// base64-encoded text, which makes the serialized form significantly more compact. The returned value can be parsed
+// using NewEncryptedValue. Returns an error if the provided value cannot be represented using CBOR: values in the
+// legacy format and values whose JSON content contains nested objects or arrays (such as THRESHOLD values) are not
+// supported.
+func ToSerializableCBOR(ev EncryptedValue) (SerializedEncryptedValue, error) {
+	if _, ok := ev.(*legacyEncryptedValue); ok {
+		return "", fmt.Errorf("values in the legacy format cannot be serialized as CBOR")
+	}
+	jsonBytes, err := json.Marshal(ev)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal for EncryptedValue of type %T failed: %v", ev, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return "", err
+	}
+
+	m := make(cborMap, len(fields))
+	for k, v := range fields {
+		switch v := v.(type) {
+		case string:
+			if !cborBinaryFields[k] {
+				m[k] = v
+				continue
+			}
+			b, err := base64.StdEncoding.DecodeString(v)
+			if err != nil {
+				return "", fmt.Errorf("failed to base64-decode field %q: %v", k, err)
+			}
+			m[k] = b
+		case json.Number:
+			n, err := strconv.ParseUint(v.String(), 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("field %q is not an unsigned integer", k)
+			}
+			m[k] = n
+		default:
+			return "", fmt.Errorf("encrypted value of type %T cannot be serialized as CBOR: field %q is of unsupported type %T", ev, k, v)
+		}
+	}
+	cborBytes, err := m.encode()
+	if err != nil {
+		return "", err
+	}
+	return SerializedEncryptedValue(encCBORPrefix + base64.StdEncoding.EncodeToString(cborBytes)), nil
+}
+
+// newEncryptedValueFromCBOR creates an EncryptedValue from the base64-encoded CBOR content of a value of the form
+// "encc:...".
+func newEncryptedValueFromCBOR(contentB64 string) (EncryptedValue, error) {
+	cborBytes, err := base64.StdEncoding.DecodeString(contentB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base64-decode content: %v", err)
+	}
+	m, err := decodeCBORMap(cborBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// convert to the JSON representation so that the value is parsed and validated in the same manner as "enc:..."
+	// values
+	fields := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case []byte:
+			if !cborBinaryFields[k] {
+				return nil, fmt.Errorf("invalid CBOR encrypted value: field %q must be a text string", k)
+			}
+			fields[k] = base64.StdEncoding.EncodeToString(v)
+		case string:
+			if cborBinaryFields[k] {
+				return nil, fmt.Errorf("invalid CBOR encrypted value: field %q must be a byte string", k)
+			}
+			fields[k] = v
+		case uint64:
+			fields[k] = json.Number(strconv.FormatUint(v, 10))
+		}
+	}
+	jsonBytes, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var evWrapper encryptedValWrapper
+	if err := json.Unmarshal(jsonBytes, &evWrapper); err != nil {
+		return nil, err
+	}
+	return evWrapper.val, nil
+}
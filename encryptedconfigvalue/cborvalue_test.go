@@ -0,0 +1,69 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSerializableCBOR(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	segmentedEncrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(4)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name      string
+		encrypter encryptedconfigvalue.Encrypter
+		kp        encryptedconfigvalue.KeyPair
+	}{
+		{"AES", encryptedconfigvalue.AES.Encrypter(), aesKP},
+		{"AES with key hint", encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.AES.Encrypter(), "device"), aesKP},
+		{"RSA", encryptedconfigvalue.RSA.Encrypter(), rsaKP},
+		{"RSA envelope", encryptedconfigvalue.NewRSAEnvelopeEncrypter(), rsaKP},
+		{"segmented AES", segmentedEncrypter, aesKP},
+	} {
+		ev, err := currCase.encrypter.Encrypt("secret value", currCase.kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		serialized, err := encryptedconfigvalue.ToSerializableCBOR(ev)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.True(t, strings.HasPrefix(string(serialized), "encc:"), "Case %d: %s", i, currCase.name)
+		assert.Less(t, len(serialized), len(ev.ToSerializable()), "Case %d: %s", i, currCase.name)
+
+		parsed, err := encryptedconfigvalue.NewEncryptedValueFromSerialized(serialized)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, ev.ToSerializable(), parsed.ToSerializable(), "Case %d: %s", i, currCase.name)
+
+		decrypted, err := parsed.Decrypt(currCase.kp.DecryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, "secret value", decrypted, "Case %d: %s", i, currCase.name)
+
+		canonical, err := encryptedconfigvalue.IsCanonical(string(serialized))
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.True(t, canonical, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestToSerializableCBORUnsupported(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	thresholdEV, err := encryptedconfigvalue.EncryptThreshold("secret", 1, aesKP.EncryptionKey)
+	require.NoError(t, err)
+
+	_, err = encryptedconfigvalue.ToSerializableCBOR(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal))
+	assert.EqualError(t, err, "values in the legacy format cannot be serialized as CBOR")
+
+	_, err = encryptedconfigvalue.ToSerializableCBOR(thresholdEV)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be serialized as CBOR")
+}
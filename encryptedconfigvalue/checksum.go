@@ -0,0 +1,49 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+// checksumSeparator separates a serialized encrypted value from its checksum. It never occurs in the base64 content
+// of a serialized value, so the checksum suffix is unambiguous.
+const checksumSeparator = "#"
+
+// ErrChecksumMismatch is returned by NewEncryptedValue when the checksum suffix of a serialized value does not match
+// the value.
+var ErrChecksumMismatch = errors.New("checksum mismatch, value may be corrupted")
+
+// ToSerializableChecksummed returns the serialized form of the provided EncryptedValue (as returned by ToSerializable)
+// followed by a checksum suffix, which is of the form "enc:<base64-text>#<crc32>", where <crc32> is the CRC-32 (IEEE)
+// checksum of "enc:<base64-text>" as 8 lowercase hexadecimal digits. The checksum detects accidental corruption of the
+// value (for example, by an incomplete copy and paste) when it is parsed using NewEncryptedValue; it does not protect
+// against deliberate modification.
+func ToSerializableChecksummed(ev EncryptedValue) SerializedEncryptedValue {
+	serialized := string(ev.ToSerializable())
+	return SerializedEncryptedValue(serialized + checksumSeparator + valueChecksum(serialized))
+}
+
+// stripChecksum returns the provided serialized value without its checksum suffix. Returns the provided value unchanged
+// if it does not have a checksum suffix, and ErrChecksumMismatch if the suffix does not match the value.
+func stripChecksum(evStr string) (string, error) {
+	idx := strings.LastIndex(evStr, checksumSeparator)
+	if idx == -1 {
+		return evStr, nil
+	}
+	serialized, checksum := evStr[:idx], evStr[idx+len(checksumSeparator):]
+	if valueChecksum(serialized) != checksum {
+		return "", ErrChecksumMismatch
+	}
+	return serialized, nil
+}
+
+// valueChecksum returns the CRC-32 (IEEE) checksum of the provided serialized value as 8 lowercase hexadecimal digits.
+func valueChecksum(serialized string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(serialized)))
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToSerializableChecksummed(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	ev := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+
+	checksummed := encryptedconfigvalue.ToSerializableChecksummed(ev)
+	assert.Regexp(t, `^`+string(testAESEncryptedVal)+`#[0-9a-f]{8}$`, string(checksummed))
+
+	parsed, err := encryptedconfigvalue.NewEncryptedValueFromSerialized(checksummed)
+	require.NoError(t, err)
+	decrypted, err := parsed.Decrypt(key)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", decrypted)
+	alg, err := encryptedconfigvalue.RequiredKeyAlgorithm(string(checksummed))
+	require.NoError(t, err)
+	assert.Equal(t, encryptedconfigvalue.AES, alg)
+
+	checksum := string(checksummed)[strings.Index(string(checksummed), "#"):]
+	for i, currCase := range []struct {
+		name  string
+		input string
+	}{
+		{"truncated value", string(testAESEncryptedVal)[:len(testAESEncryptedVal)-4] + checksum},
+		{"modified value", strings.Replace(string(testAESEncryptedVal), "enc:e", "enc:f", 1) + checksum},
+		{"truncated checksum", string(checksummed)[:len(checksummed)-1]},
+		{"empty checksum", string(testAESEncryptedVal) + "#"},
+	} {
+		_, err := encryptedconfigvalue.NewEncryptedValue(currCase.input)
+		assert.Equal(t, encryptedconfigvalue.ErrChecksumMismatch, err, "Case %d: %s", i, currCase.name)
+		_, err = encryptedconfigvalue.RequiredKeyAlgorithm(currCase.input)
+		assert.Equal(t, encryptedconfigvalue.ErrChecksumMismatch, err, "Case %d: %s", i, currCase.name)
+	}
+}
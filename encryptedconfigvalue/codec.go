@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Codec converts EncryptedValues to and from a serialized form. A value marshaled using a Codec can be unmarshaled
+// using the same Codec.
+type Codec interface {
+	// Marshal returns the serialized form of the provided value. Returns an error if the value cannot be represented
+	// using this Codec.
+	Marshal(ev EncryptedValue) ([]byte, error)
+
+	// Unmarshal returns the EncryptedValue represented by the provided serialized form. Returns an error if the
+	// provided data is not a valid serialized form for this Codec.
+	Unmarshal(data []byte) (EncryptedValue, error)
+}
+
+// DefaultCodec returns the Codec for the default serialized form of encrypted values, which is JSONCodec.
+func DefaultCodec() Codec {
+	return JSONCodec()
+}
+
+type jsonCodec struct{}
+
+// JSONCodec returns a Codec for the serialized form "enc:<base64-encoded-content>" returned by ToSerializable, where
+// the content is the JSON representation of the value (or the ciphertext, for values in the legacy format).
+func JSONCodec() Codec {
+	return jsonCodec{}
+}
+
+func (jsonCodec) Marshal(ev EncryptedValue) ([]byte, error) {
+	return ev.ToSerializableBytes()
+}
+
+func (jsonCodec) Unmarshal(data []byte) (EncryptedValue, error) {
+	return newEncryptedValueFromJSONForm(string(data))
+}
+
+type cborCodec struct{}
+
+// CBORCodec returns a Codec for the serialized form "encc:<base64-encoded-CBOR>" returned by ToSerializableCBOR.
+func CBORCodec() Codec {
+	return cborCodec{}
+}
+
+func (cborCodec) Marshal(ev EncryptedValue) ([]byte, error) {
+	serialized, err := ToSerializableCBOR(ev)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(serialized), nil
+}
+
+func (cborCodec) Unmarshal(data []byte) (EncryptedValue, error) {
+	evStr := string(data)
+	if !strings.HasPrefix(evStr, encCBORPrefix) {
+		return nil, fmt.Errorf(`CBOR encrypted value must be of the form "%s..."`, encCBORPrefix)
+	}
+	return newEncryptedValueFromCBOR(evStr[len(encCBORPrefix):])
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	ev := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+
+	for i, currCase := range []struct {
+		name   string
+		codec  encryptedconfigvalue.Codec
+		prefix string
+	}{
+		{
+			name:   "default",
+			codec:  encryptedconfigvalue.DefaultCodec(),
+			prefix: "enc:",
+		},
+		{
+			name:   "JSON",
+			codec:  encryptedconfigvalue.JSONCodec(),
+			prefix: "enc:",
+		},
+		{
+			name:   "CBOR",
+			codec:  encryptedconfigvalue.CBORCodec(),
+			prefix: "encc:",
+		},
+	} {
+		marshaled, err := currCase.codec.Marshal(ev)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.True(t, strings.HasPrefix(string(marshaled), currCase.prefix), "Case %d: %s", i, currCase.name)
+
+		// values marshaled by every codec can be parsed by the codec and by NewEncryptedValue
+		for _, unmarshal := range []func([]byte) (encryptedconfigvalue.EncryptedValue, error){
+			currCase.codec.Unmarshal,
+			func(data []byte) (encryptedconfigvalue.EncryptedValue, error) {
+				return encryptedconfigvalue.NewEncryptedValue(string(data))
+			},
+		} {
+			unmarshaled, err := unmarshal(marshaled)
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			decrypted, err := unmarshaled.Decrypt(key)
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, "plaintext", decrypted, "Case %d: %s", i, currCase.name)
+		}
+	}
+
+	marshaled, err := encryptedconfigvalue.DefaultCodec().Marshal(ev)
+	require.NoError(t, err)
+	assert.Equal(t, string(testAESEncryptedVal), string(marshaled))
+
+	_, err = encryptedconfigvalue.CBORCodec().Unmarshal([]byte(testAESEncryptedVal))
+	assert.EqualError(t, err, `CBOR encrypted value must be of the form "encc:..."`)
+}
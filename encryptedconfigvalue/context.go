@@ -0,0 +1,123 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+)
+
+// contextEncrypter is implemented by Encrypter implementations that can bind the values they create to a context.
+// The context is authenticated as additional data (AES-GCM) or as the OAEP label (RSA-OAEP): it is not stored in the
+// value, and the value can only be decrypted by providing the same context.
+type contextEncrypter interface {
+	encryptWithContext(input string, key KeyWithType, context []byte) (EncryptedValue, error)
+}
+
+// contextDecrypter is implemented by EncryptedValue implementations that can be bound to a context.
+type contextDecrypter interface {
+	decryptWithContext(key KeyWithType, context []byte) (string, error)
+}
+
+// EncryptInJSON returns a copy of the provided JSON document in which every string value whose JSON path (in JSON
+// Pointer form) matches the provided predicate is replaced with the serialized form of the result of encrypting it
+// using the provided key (using the Encrypter for the algorithm type of the key). Values that are already of the form
+// "enc:...", "encc:..." or "encs:..." are not modified. All other content of the document is preserved exactly.
+//
+// Every value is bound to the provided context, which should identify the document (for example, "prod/db.json").
+// A value that is bound to a context can only be decrypted using DecryptAllInJSONWithContext with the same context, so
+// a value that is copied into a document with a different context fails to decrypt. An empty context does not bind
+// values, and the resulting values can be decrypted by all of the other functions in this package.
+//
+// Binding values to a context is a tradeoff: legitimate changes that alter the context (such as renaming a document or
+// moving values between documents) require the affected values to be decrypted using the old context and re-encrypted
+// using the new one. The context is therefore best chosen to identify the logical configuration (which rarely changes)
+// rather than a digest of the document content (which would change on every edit).
+func EncryptInJSON(data []byte, match func(path string) bool, key KeyWithType, context string) ([]byte, error) {
+	encrypter, ok := key.Type.AlgorithmType().Encrypter().(contextEncrypter)
+	if !ok {
+		return nil, fmt.Errorf("keys of type %s cannot be used to encrypt values bound to a context", key.Type)
+	}
+
+	var nodes []jsonStringValue
+	var encrypted []string
+	if err := walkJSONStringValues(data, func(node jsonStringValue) error {
+		if isEncryptedValueString(node.value) || !match(node.path) {
+			return nil
+		}
+		ev, err := encrypter.encryptWithContext(node.value, key, []byte(context))
+		if err != nil {
+			return fmt.Errorf("failed to encrypt value at %q: %v", node.path, err)
+		}
+		nodes = append(nodes, node)
+		encrypted = append(encrypted, string(ev.ToSerializable()))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return replaceJSONStringValues(data, nodes, encrypted), nil
+}
+
+// DecryptAllInJSONWithContext behaves like DecryptAllInJSON, but decrypts every value using the provided context,
+// which must be the context to which the values were bound by EncryptInJSON. Values that were bound to a different
+// context (or that were not bound to a context, if the provided context is not empty) fail to decrypt. Values whose
+// type does not support contexts (such as values in the legacy format) also fail to decrypt if the provided context is
+// not empty.
+func DecryptAllInJSONWithContext(data []byte, key KeyWithType, context string) ([]byte, error) {
+	nodes, err := encryptedJSONStringValues(data)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]string, len(nodes))
+	for i, node := range nodes {
+		ev, err := NewEncryptedValue(node.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse encrypted value at %q: %v", node.path, err)
+		}
+		if decrypted[i], err = decryptWithContext(ev, key, context); err != nil {
+			return nil, fmt.Errorf("failed to decrypt value at %q: %v", node.path, err)
+		}
+	}
+	return replaceJSONStringValues(data, nodes, decrypted), nil
+}
+
+// decryptWithContext decrypts the provided value using the provided key and context. If the context is empty, this is
+// equivalent to calling Decrypt.
+func decryptWithContext(ev EncryptedValue, key KeyWithType, context string) (string, error) {
+	return decryptWithOptions(ev, key, decrypterOptions{
+		context: context,
+	})
+}
+
+// decryptWithOptions decrypts the provided value using the provided key and options. If the value has a policy, the
+// policy enforcer of the options is invoked after the value is decrypted, and an error is returned if the options do
+// not have a policy enforcer.
+func decryptWithOptions(ev EncryptedValue, key KeyWithType, o decrypterOptions) (string, error) {
+	var policy []byte
+	if policyVal, ok := ev.(policyValue); ok {
+		policy = policyVal.policy()
+	}
+	if policy == nil && o.context == "" {
+		return ev.Decrypt(key)
+	}
+	if policy != nil && o.policyEnforcer == nil {
+		return "", errPolicyNotEnforced
+	}
+	decrypter, ok := ev.(contextDecrypter)
+	if !ok {
+		return "", fmt.Errorf("encrypted value of type %T does not support contexts", ev)
+	}
+	decrypted, err := decrypter.decryptWithContext(key, []byte(o.context))
+	if err != nil || policy == nil {
+		return decrypted, err
+	}
+	policyMap, err := decodePolicy(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode policy: %v", err)
+	}
+	if err := o.policyEnforcer(policyMap); err != nil {
+		return "", fmt.Errorf("decryption rejected by policy enforcer: %w", err)
+	}
+	return decrypted, nil
+}
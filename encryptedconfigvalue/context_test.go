@@ -0,0 +1,84 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptInJSONWithContext(t *testing.T) {
+	const input = `{"db": {"user": "admin", "password": "hunter2"}, "token": "abc"}`
+	matchSecrets := func(path string) bool {
+		return path == "/db/password" || path == "/token"
+	}
+
+	for i, currAlg := range []encryptedconfigvalue.AlgorithmType{
+		encryptedconfigvalue.AES,
+		encryptedconfigvalue.RSA,
+	} {
+		kp, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+
+		encrypted, err := encryptedconfigvalue.EncryptInJSON([]byte(input), matchSecrets, kp.EncryptionKey, "prod/app.json")
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+
+		var values struct {
+			DB struct {
+				User     string `json:"user"`
+				Password string `json:"password"`
+			} `json:"db"`
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(encrypted, &values), "Case %d: %s", i, currAlg)
+		assert.Equal(t, "admin", values.DB.User, "Case %d: %s", i, currAlg)
+		assert.True(t, strings.HasPrefix(values.DB.Password, "enc:"), "Case %d: %s", i, currAlg)
+		assert.True(t, strings.HasPrefix(values.Token, "enc:"), "Case %d: %s", i, currAlg)
+
+		decrypted, err := encryptedconfigvalue.DecryptAllInJSONWithContext(encrypted, kp.DecryptionKey, "prod/app.json")
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		assert.Equal(t, input, string(decrypted), "Case %d: %s", i, currAlg)
+
+		// values fail to decrypt using a different context or without a context
+		_, err = encryptedconfigvalue.DecryptAllInJSONWithContext(encrypted, kp.DecryptionKey, "staging/app.json")
+		require.Error(t, err, "Case %d: %s", i, currAlg)
+		assert.Contains(t, err.Error(), `failed to decrypt value at "/db/password"`, "Case %d: %s", i, currAlg)
+		_, err = encryptedconfigvalue.DecryptAllInJSON(encrypted, kp.DecryptionKey)
+		assert.Error(t, err, "Case %d: %s", i, currAlg)
+	}
+}
+
+func TestEncryptInJSONWithoutContext(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	input := fmt.Sprintf(`["secret", "%s"]`, testAESEncryptedVal)
+
+	encrypted, err := encryptedconfigvalue.EncryptInJSON([]byte(input), func(string) bool {
+		return true
+	}, kp.EncryptionKey, "")
+	require.NoError(t, err)
+
+	var values []string
+	require.NoError(t, json.Unmarshal(encrypted, &values))
+	// existing encrypted values are not modified
+	assert.Equal(t, string(testAESEncryptedVal), values[1])
+
+	// values encrypted without a context can be decrypted normally
+	decrypted, err := encryptedconfigvalue.MustNewEncryptedValue(values[0]).Decrypt(kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+}
+
+func TestDecryptAllInJSONWithContextUnsupportedValue(t *testing.T) {
+	_, err := encryptedconfigvalue.DecryptAllInJSONWithContext([]byte(fmt.Sprintf(`["%s"]`, javaLegacyAESEncryptedVal)),
+		encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaAESKey), "prod/app.json")
+	assert.EqualError(t, err, `failed to decrypt value at "/0": encrypted value of type *encryptedconfigvalue.legacyEncryptedValue does not support contexts`)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"time"
+)
+
+// Decrypter decrypts EncryptedValues using a configured key. Code that decrypts values can depend on a Decrypter
+// rather than on a key, which allows the key to be provided (or decryption to be stubbed) by its caller.
+type Decrypter interface {
+	// Decrypt returns the result of decrypting the provided value. Returns an error if the value cannot be decrypted
+	// using the configuration of this Decrypter.
+	Decrypt(ev EncryptedValue) (string, error)
+}
+
+// DecrypterFunc is an adapter that allows an ordinary function to be used as a Decrypter.
+type DecrypterFunc func(ev EncryptedValue) (string, error)
+
+// Decrypt returns the result of calling f.
+func (f DecrypterFunc) Decrypt(ev EncryptedValue) (string, error) {
+	return f(ev)
+}
+
+// Option configures the Decrypter returned by NewDecrypter.
+type Option func(*decrypterOptions)
+
+type decrypterOptions struct {
+	context             string
+	policyEnforcer      PolicyEnforcer
+	asymmetricRateLimit *rateLimit
+	auditSink           AuditSink
+}
+
+// WithContext returns an Option that configures a Decrypter to decrypt values using the provided context, which must
+// be the context to which the values were bound (see EncryptInJSON). An empty context is equivalent to not providing
+// this option.
+func WithContext(context string) Option {
+	return func(o *decrypterOptions) {
+		o.context = context
+	}
+}
+
+// WithPolicyEnforcer returns an Option that configures a Decrypter to invoke the provided enforcer with the policy of
+// every value that has a policy (see WithPolicy) before returning its plaintext. A Decrypter that is not configured
+// using this option cannot decrypt values that have a policy.
+func WithPolicyEnforcer(enforcer PolicyEnforcer) Option {
+	return func(o *decrypterOptions) {
+		o.policyEnforcer = enforcer
+	}
+}
+
+// NewDecrypter returns a Decrypter that decrypts values using the provided key, configured using the provided options.
+// The key may be obtained from any source, such as a KeySource.
+func NewDecrypter(key KeyWithType, opts ...Option) Decrypter {
+	var o decrypterOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var limiter *tokenBucket
+	if o.asymmetricRateLimit != nil {
+		limiter = newTokenBucket(*o.asymmetricRateLimit, time.Now)
+	}
+	return DecrypterFunc(func(ev EncryptedValue) (string, error) {
+		if limiter != nil && requiresAsymmetricOperation(ev, key) && !limiter.take() {
+			return "", ErrRateLimited
+		}
+		decrypted, err := decryptWithOptions(ev, key, o)
+		if o.auditSink != nil {
+			o.auditSink.RecordDecrypt(newAuditRecord(ev, key, err != nil))
+		}
+		return decrypted, err
+	})
+}
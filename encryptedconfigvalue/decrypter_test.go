@@ -0,0 +1,77 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDecrypter(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	decrypted, err := encryptedconfigvalue.NewDecrypter(key).Decrypt(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", decrypted)
+
+	decrypted, err = encryptedconfigvalue.NewDecrypter(key, encryptedconfigvalue.WithContext("")).Decrypt(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", decrypted)
+}
+
+func TestNewDecrypterWithContext(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypted, err := encryptedconfigvalue.EncryptInJSON([]byte(`{"password":"secret"}`), func(string) bool { return true }, kp.EncryptionKey, "prod/db.json")
+	require.NoError(t, err)
+	var values struct {
+		Password string `json:"password"`
+	}
+	require.NoError(t, json.Unmarshal(encrypted, &values))
+	ev := encryptedconfigvalue.MustNewEncryptedValue(values.Password)
+
+	decrypted, err := encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, encryptedconfigvalue.WithContext("prod/db.json")).Decrypt(ev)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+
+	_, err = encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, encryptedconfigvalue.WithContext("dev/db.json")).Decrypt(ev)
+	assert.Error(t, err)
+	_, err = encryptedconfigvalue.NewDecrypter(kp.DecryptionKey).Decrypt(ev)
+	assert.Error(t, err)
+}
+
+func TestDecrypterFunc(t *testing.T) {
+	var decrypter encryptedconfigvalue.Decrypter = encryptedconfigvalue.DecrypterFunc(func(ev encryptedconfigvalue.EncryptedValue) (string, error) {
+		return "", fmt.Errorf("stubbed")
+	})
+	_, err := decrypter.Decrypt(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	assert.EqualError(t, err, "stubbed")
+}
+
+func TestNewDecrypterWithAsymmetricRateLimit(t *testing.T) {
+	rsaKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testRSAEncryptedValPrivKey)
+	rsaEV := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testRSAEncryptedVal)
+	decrypter := encryptedconfigvalue.NewDecrypter(rsaKey, encryptedconfigvalue.WithAsymmetricRateLimit(0.001, 2))
+	for i := 0; i < 2; i++ {
+		decrypted, err := decrypter.Decrypt(rsaEV)
+		require.NoError(t, err, "Decrypt %d", i)
+		assert.Equal(t, "plaintext", decrypted, "Decrypt %d", i)
+	}
+	_, err := decrypter.Decrypt(rsaEV)
+	assert.Equal(t, encryptedconfigvalue.ErrRateLimited, err)
+
+	// symmetric decryption is not limited
+	aesKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	aesEV := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+	aesDecrypter := encryptedconfigvalue.NewDecrypter(aesKey, encryptedconfigvalue.WithAsymmetricRateLimit(0.001, 1))
+	for i := 0; i < 5; i++ {
+		_, err := aesDecrypter.Decrypt(aesEV)
+		require.NoError(t, err, "Decrypt %d", i)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// DecryptStreamProgress decrypts the provided value using the provided key and writes the plaintext to w. If progress is
+// non-nil, it is called with a done value of 0 before any plaintext is written and then after every write with the
+// total number of plaintext bytes written so far. total is always the size of the full plaintext, so the final call
+// has done equal to total.
+//
+// Values created by the encrypter returned by NewAESGCMSegmentedEncrypter are decrypted one segment at a time: every
+// segment is authenticated before its plaintext is written, and progress is reported after every segment. If a
+// segment fails to authenticate, an error is returned and no later segment is written, but the plaintext of the
+// segments before it will already have been written to w. The plaintext of all other values (including envelope
+// values, whose payload is a single AES-GCM ciphertext) is decrypted and authenticated in full before it is written in
+// a single write.
+func DecryptStreamProgress(ev EncryptedValue, key KeyWithType, w io.Writer, progress func(done, total int64)) error {
+	if progress == nil {
+		progress = func(done, total int64) {}
+	}
+
+	segmentedEV, ok := ev.(*aesGCMSegmentedEncryptedValue)
+	if !ok {
+		decrypted, err := ev.Decrypt(key)
+		if err != nil {
+			return err
+		}
+		total := int64(len(decrypted))
+		progress(0, total)
+		if _, err := io.WriteString(w, decrypted); err != nil {
+			return fmt.Errorf("failed to write plaintext: %v", err)
+		}
+		progress(total, total)
+		return nil
+	}
+
+	segmentedCipher, err := encryption.AESGCMSegmentedCipherWithSegmentSize(segmentedEV.segmentSize)
+	if err != nil {
+		return err
+	}
+	total := segmentedEV.plaintextSize()
+	progress(0, total)
+	var done int64
+	encrypted := append(append([]byte{}, segmentedEV.salt...), segmentedEV.encrypted...)
+	return segmentedCipher.DecryptSegments(encrypted, key.Key, func(segment []byte) error {
+		defer zeroBytes(segment)
+		if _, err := w.Write(segment); err != nil {
+			return fmt.Errorf("failed to write plaintext: %v", err)
+		}
+		done += int64(len(segment))
+		progress(done, total)
+		return nil
+	})
+}
+
+// plaintextSize returns the size of the plaintext of the value, which is the size of its ciphertext without the tag of
+// every segment. Every segment except the last contains segmentSize bytes of plaintext.
+func (ev *aesGCMSegmentedEncryptedValue) plaintextSize() int64 {
+	encryptedSegmentSize := int64(ev.segmentSize) + aesGCMDefaultTagSizeBytes
+	numSegments := (int64(len(ev.encrypted)) + encryptedSegmentSize - 1) / encryptedSegmentSize
+	if numSegments == 0 {
+		numSegments = 1
+	}
+	if size := int64(len(ev.encrypted)) - numSegments*aesGCMDefaultTagSizeBytes; size > 0 {
+		return size
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptStreamProgress(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	segmentedEncrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(10)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name         string
+		encrypter    encryptedconfigvalue.Encrypter
+		kp           encryptedconfigvalue.KeyPair
+		input        string
+		wantProgress [][2]int64
+	}{
+		{
+			name:         "segmented value",
+			encrypter:    segmentedEncrypter,
+			kp:           aesKP,
+			input:        strings.Repeat("a", 25),
+			wantProgress: [][2]int64{{0, 25}, {10, 25}, {20, 25}, {25, 25}},
+		},
+		{
+			name:         "segmented value with full final segment",
+			encrypter:    segmentedEncrypter,
+			kp:           aesKP,
+			input:        strings.Repeat("a", 20),
+			wantProgress: [][2]int64{{0, 20}, {10, 20}, {20, 20}},
+		},
+		{
+			name:         "empty segmented value",
+			encrypter:    segmentedEncrypter,
+			kp:           aesKP,
+			input:        "",
+			wantProgress: [][2]int64{{0, 0}, {0, 0}},
+		},
+		{
+			name:         "envelope value",
+			encrypter:    encryptedconfigvalue.NewRSAEnvelopeEncrypter(),
+			kp:           rsaKP,
+			input:        strings.Repeat("a", 25),
+			wantProgress: [][2]int64{{0, 25}, {25, 25}},
+		},
+	} {
+		ev, err := currCase.encrypter.Encrypt(currCase.input, currCase.kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		var buf bytes.Buffer
+		var gotProgress [][2]int64
+		err = encryptedconfigvalue.DecryptStreamProgress(ev, currCase.kp.DecryptionKey, &buf, func(done, total int64) {
+			gotProgress = append(gotProgress, [2]int64{done, total})
+		})
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.input, buf.String(), "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.wantProgress, gotProgress, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestDecryptStreamProgressWrongKey(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(10)
+	require.NoError(t, err)
+	ev, err := encrypter.Encrypt(strings.Repeat("a", 25), kp.EncryptionKey)
+	require.NoError(t, err)
+
+	// the first segment fails to authenticate, so no plaintext is written
+	var buf bytes.Buffer
+	err = encryptedconfigvalue.DecryptStreamProgress(ev, otherKP.DecryptionKey, &buf, nil)
+	assert.EqualError(t, err, "failed to decrypt segment 0: cipher: message authentication failed")
+	assert.Empty(t, buf.String())
+}
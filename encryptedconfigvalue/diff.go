@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"sort"
+)
+
+// ChangeKind is the kind of a Change.
+type ChangeKind string
+
+const (
+	// ChangeAdded indicates that an encrypted value exists at a path in the new document but not in the old one.
+	ChangeAdded = ChangeKind("added")
+	// ChangeRemoved indicates that an encrypted value exists at a path in the old document but not in the new one.
+	ChangeRemoved = ChangeKind("removed")
+	// ChangeModified indicates that the encrypted values at a path in the old and new documents decrypt to different
+	// plaintexts.
+	ChangeModified = ChangeKind("changed")
+)
+
+// Change describes a change to the encrypted value at a path in a JSON document. It never contains the plaintext or
+// the encrypted value.
+type Change struct {
+	// Path is the JSON Pointer (RFC 6901) for the value in the document, for example "/db/password".
+	Path string
+	Kind ChangeKind
+}
+
+// DiffEncryptedValues returns the changes to the encrypted values (see DecryptAllInJSON) between the provided JSON
+// documents, sorted by path. Values at the same path are compared by decrypting them using the provided key, so a value
+// that was re-encrypted (for example, using a new IV) without changing its plaintext is not reported as changed.
+// Returns an error that identifies the JSON path of the first value that cannot be parsed or decrypted.
+func DiffEncryptedValues(oldData, newData []byte, key KeyWithType) ([]Change, error) {
+	oldValues, err := decryptedValuesByPath(oldData, key)
+	if err != nil {
+		return nil, fmt.Errorf("old document: %v", err)
+	}
+	defer zeroDecryptedValues(oldValues)
+	newValues, err := decryptedValuesByPath(newData, key)
+	if err != nil {
+		return nil, fmt.Errorf("new document: %v", err)
+	}
+	defer zeroDecryptedValues(newValues)
+
+	var changes []Change
+	for path, oldValue := range oldValues {
+		newValue, ok := newValues[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Path: path, Kind: ChangeRemoved})
+		case subtle.ConstantTimeCompare(oldValue, newValue) != 1:
+			changes = append(changes, Change{Path: path, Kind: ChangeModified})
+		}
+	}
+	for path := range newValues {
+		if _, ok := oldValues[path]; !ok {
+			changes = append(changes, Change{Path: path, Kind: ChangeAdded})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// decryptedValuesByPath returns a map from the JSON path of every encrypted value in the provided document to its
+// decrypted value. If an error is returned, the values that were already decrypted are zeroed.
+func decryptedValuesByPath(data []byte, key KeyWithType) (map[string][]byte, error) {
+	nodes, err := encryptedJSONStringValues(data)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]byte, len(nodes))
+	for _, node := range nodes {
+		decrypted, err := decryptJSONStringValue(node, key)
+		if err != nil {
+			zeroDecryptedValues(values)
+			return nil, err
+		}
+		values[node.path] = []byte(decrypted)
+	}
+	return values, nil
+}
+
+func zeroDecryptedValues(values map[string][]byte) {
+	for _, v := range values {
+		zeroBytes(v)
+	}
+}
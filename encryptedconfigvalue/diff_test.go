@@ -0,0 +1,48 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffEncryptedValues(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypt := func(plaintext string) string {
+		ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt(plaintext, kp.EncryptionKey)
+		require.NoError(t, err)
+		return string(ev.ToSerializable())
+	}
+
+	oldData := fmt.Sprintf(`{"unchanged": %q, "reencrypted": %q, "changed": %q, "removed": %q, "plain": "a"}`,
+		encrypt("one"), encrypt("two"), encrypt("three"), encrypt("four"))
+	newData := fmt.Sprintf(`{"unchanged": %q, "reencrypted": %q, "changed": %q, "added": [%q], "plain": "b"}`,
+		encrypt("one"), encrypt("two"), encrypt("3"), encrypt("five"))
+	changes, err := encryptedconfigvalue.DiffEncryptedValues([]byte(oldData), []byte(newData), kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, []encryptedconfigvalue.Change{
+		{Path: "/added/0", Kind: encryptedconfigvalue.ChangeAdded},
+		{Path: "/changed", Kind: encryptedconfigvalue.ChangeModified},
+		{Path: "/removed", Kind: encryptedconfigvalue.ChangeRemoved},
+	}, changes)
+
+	changes, err = encryptedconfigvalue.DiffEncryptedValues([]byte(oldData), []byte(oldData), kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffEncryptedValuesError(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.DiffEncryptedValues([]byte(`{}`), []byte(fmt.Sprintf(`{"a": %q}`, testAESEncryptedVal)), kp.DecryptionKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `new document: failed to decrypt value at "/a"`)
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// dotenvEntry is a variable assignment that occurs on a single line of a dotenv file.
+type dotenvEntry struct {
+	// name is the name of the variable.
+	name string
+	// value is the value of the variable with quotes removed and escape sequences resolved.
+	value string
+	// valueStart is the offset of the first byte of the raw value (including any quotes) in the line.
+	valueStart int
+	// valueEnd is the offset just past the last byte of the raw value (including any quotes) in the line.
+	valueEnd int
+}
+
+// EncryptDotenv encrypts the values of the variables with the provided names in the dotenv file at the provided path
+// using the provided key and rewrites the file. The file is replaced atomically (the new content is written to a
+// temporary file in the same directory that is then renamed over the original), so the original file is never left
+// partially written. Values are encrypted using the Encrypter for the algorithm type of the key. Comments, blank lines,
+// the order of variables and the values of all other variables are preserved. Values that are already of the form
+// "enc:...", "encc:..." or "encs:..." are left unchanged. Returns an error if any provided name is not assigned in the
+// file, in which case the file is not modified.
+func EncryptDotenv(path string, keys []string, key KeyWithType) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read dotenv file: %v", err)
+	}
+
+	toEncrypt := make(map[string]bool)
+	for _, name := range keys {
+		toEncrypt[name] = false
+	}
+	encrypter := key.Type.AlgorithmType().Encrypter()
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		entry, ok, err := parseDotenvLine(line)
+		if err != nil {
+			return fmt.Errorf("invalid dotenv file: line %d: %v", i+1, err)
+		}
+		if !ok {
+			continue
+		}
+		if _, ok := toEncrypt[entry.name]; !ok {
+			continue
+		}
+		toEncrypt[entry.name] = true
+		if isEncryptedValueString(entry.value) {
+			continue
+		}
+		ev, err := encrypter.Encrypt(entry.value, key)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt variable %q: %v", entry.name, err)
+		}
+		lines[i] = line[:entry.valueStart] + string(ev.ToSerializable()) + line[entry.valueEnd:]
+	}
+	for _, name := range keys {
+		if !toEncrypt[name] {
+			return fmt.Errorf("variable %q is not defined in dotenv file", name)
+		}
+	}
+
+	if err := writeFileAtomic(path, []byte(strings.Join(lines, "\n"))); err != nil {
+		return fmt.Errorf("failed to write dotenv file: %v", err)
+	}
+	return nil
+}
+
+// DecryptDotenv returns all of the variables defined in the dotenv file at the provided path. Values of the form
+// "enc:..." or "encc:..." are replaced with the result of decrypting them using the provided key, and an error is
+// returned for values of the form "encs:...", which must be verified using VerifyAndParse. If a variable is assigned
+// more than once, the last assignment is used.
+func DecryptDotenv(path string, key KeyWithType) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file: %v", err)
+	}
+	vars := make(map[string]string)
+	for i, line := range strings.Split(string(content), "\n") {
+		entry, ok, err := parseDotenvLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dotenv file: line %d: %v", i+1, err)
+		}
+		if !ok {
+			continue
+		}
+		value := entry.value
+		if isEncryptedValueString(value) {
+			ev, err := NewEncryptedValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse encrypted value for variable %q: %v", entry.name, err)
+			}
+			if value, err = ev.Decrypt(key); err != nil {
+				return nil, fmt.Errorf("failed to decrypt value for variable %q: %v", entry.name, err)
+			}
+		}
+		vars[entry.name] = value
+	}
+	return vars, nil
+}
+
+// parseDotenvLine parses a single line of a dotenv file. Returns false if the line is blank or a comment. Lines are of
+// the form "[export ]NAME=VALUE", where VALUE is either unquoted (in which case it ends at the first " #" and trailing
+// whitespace is ignored), single-quoted (in which case its content is literal) or double-quoted (in which case the
+// escape sequences \n, \r, \t, \" and \\ are supported).
+func parseDotenvLine(line string) (dotenvEntry, bool, error) {
+	line = strings.TrimSuffix(line, "\r")
+	trimmed := strings.TrimLeft(line, " \t")
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return dotenvEntry{}, false, nil
+	}
+	offset := len(line) - len(trimmed)
+	if strings.HasPrefix(trimmed, "export ") {
+		rest := strings.TrimLeft(trimmed[len("export "):], " \t")
+		offset += len(trimmed) - len(rest)
+		trimmed = rest
+	}
+
+	eqIdx := strings.Index(trimmed, "=")
+	if eqIdx == -1 {
+		return dotenvEntry{}, false, fmt.Errorf("expected assignment of the form NAME=VALUE")
+	}
+	name := strings.TrimRight(trimmed[:eqIdx], " \t")
+	if name == "" || strings.ContainsAny(name, " \t\"'") {
+		return dotenvEntry{}, false, fmt.Errorf("invalid variable name")
+	}
+
+	start := offset + eqIdx + 1
+	for start < len(line) && (line[start] == ' ' || line[start] == '\t') {
+		start++
+	}
+	raw := line[start:]
+	entry := dotenvEntry{
+		name:       name,
+		valueStart: start,
+	}
+
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		closeIdx := strings.Index(raw[1:], "'")
+		if closeIdx == -1 {
+			return dotenvEntry{}, false, fmt.Errorf("unterminated quoted value for variable %q", name)
+		}
+		entry.value = raw[1 : closeIdx+1]
+		entry.valueEnd = start + closeIdx + 2
+	case strings.HasPrefix(raw, `"`):
+		var sb strings.Builder
+		closeIdx := -1
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '"' {
+				closeIdx = i
+				break
+			}
+			if raw[i] == '\\' && i+1 < len(raw) {
+				i++
+				switch raw[i] {
+				case 'n':
+					sb.WriteByte('\n')
+				case 'r':
+					sb.WriteByte('\r')
+				case 't':
+					sb.WriteByte('\t')
+				case '"', '\\':
+					sb.WriteByte(raw[i])
+				default:
+					sb.WriteByte('\\')
+					sb.WriteByte(raw[i])
+				}
+				continue
+			}
+			sb.WriteByte(raw[i])
+		}
+		if closeIdx == -1 {
+			return dotenvEntry{}, false, fmt.Errorf("unterminated quoted value for variable %q", name)
+		}
+		entry.value = sb.String()
+		entry.valueEnd = start + closeIdx + 1
+	default:
+		value := raw
+		for i := 1; i < len(value); i++ {
+			if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+				value = value[:i]
+				break
+			}
+		}
+		value = strings.TrimRight(value, " \t")
+		entry.value = value
+		entry.valueEnd = start + len(value)
+	}
+	return entry, true, nil
+}
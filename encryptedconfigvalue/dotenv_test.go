@@ -0,0 +1,117 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDotenv(t *testing.T) {
+	const content = `# database settings
+export DB_USER=admin
+DB_PASSWORD=hunter2 # rotate monthly
+
+API_TOKEN="token with \"quotes\""
+SINGLE='literal \n value'
+ALREADY=` + string(testAESEncryptedVal) + `
+OTHER=unchanged
+`
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0640))
+
+	err := encryptedconfigvalue.EncryptDotenv(path, []string{"DB_PASSWORD", "API_TOKEN", "SINGLE", "ALREADY"}, key)
+	require.NoError(t, err)
+
+	encrypted, err := os.ReadFile(path)
+	require.NoError(t, err)
+	enc := `enc:[A-Za-z0-9+/=]+`
+	assert.Regexp(t, regexp.MustCompile(`^# database settings
+export DB_USER=admin
+DB_PASSWORD=`+enc+` # rotate monthly
+
+API_TOKEN=`+enc+`
+SINGLE=`+enc+`
+ALREADY=`+regexp.QuoteMeta(string(testAESEncryptedVal))+`
+OTHER=unchanged
+$`), string(encrypted))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	// the file is replaced atomically, so no temporary file is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ".env", entries[0].Name())
+
+	vars, err := encryptedconfigvalue.DecryptDotenv(path, key)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DB_USER":     "admin",
+		"DB_PASSWORD": "hunter2",
+		"API_TOKEN":   `token with "quotes"`,
+		"SINGLE":      `literal \n value`,
+		"ALREADY":     "plaintext",
+		"OTHER":       "unchanged",
+	}, vars)
+}
+
+func TestEncryptDotenvErrors(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+
+	for i, currCase := range []struct {
+		name    string
+		content string
+		keys    []string
+		wantErr string
+	}{
+		{
+			name:    "missing variable",
+			content: "FOO=bar\n",
+			keys:    []string{"FOO", "BAR"},
+			wantErr: `variable "BAR" is not defined in dotenv file`,
+		},
+		{
+			name:    "invalid line",
+			content: "FOO=bar\nnot an assignment\n",
+			keys:    []string{"FOO"},
+			wantErr: "invalid dotenv file: line 2: expected assignment of the form NAME=VALUE",
+		},
+		{
+			name:    "unterminated quote",
+			content: `FOO="bar` + "\n",
+			keys:    []string{"FOO"},
+			wantErr: `invalid dotenv file: line 1: unterminated quoted value for variable "FOO"`,
+		},
+	} {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte(currCase.content), 0600), "Case %d: %s", i, currCase.name)
+
+		err := encryptedconfigvalue.EncryptDotenv(path, currCase.keys, key)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+
+		// file is not modified on failure
+		got, err := os.ReadFile(path)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.content, string(got), "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestDecryptDotenvWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("SECRET="+string(testRSAEncryptedVal)+"\n"), 0600))
+
+	_, err := encryptedconfigvalue.DecryptDotenv(path, encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decrypt value for variable "SECRET"`)
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+)
+
+// EditSession supports editing the plaintext of the encrypted values in a JSON document: the document is decrypted,
+// edited (for example, in a text editor) and re-encrypted using Reencrypt. Values whose plaintext did not change keep
+// their original serialized form, so re-encrypting a document after an edit only changes the values that were edited.
+type EditSession struct {
+	encryptKey KeyWithType
+	decrypted  []byte
+	// originals maps the JSON path of every encrypted value in the original document to the value.
+	originals map[string]editSessionValue
+	// plaintextPaths contains the JSON path of every string value in the original document that was not encrypted.
+	plaintextPaths map[string]bool
+	// secrets maps the plaintext of every encrypted value in the original document to its serialized form.
+	secrets map[string]string
+}
+
+type editSessionValue struct {
+	serialized string
+	plaintext  string
+}
+
+// NewEditSession returns a new EditSession for the provided JSON document. Every encrypted value in the document (see
+// DecryptAllInJSON) is decrypted using decryptKey, and values that are changed by an edit are encrypted using
+// encryptKey (using the Encrypter for the algorithm type of encryptKey). Returns an error that identifies the JSON path
+// of the first value that cannot be parsed or decrypted.
+func NewEditSession(data []byte, decryptKey, encryptKey KeyWithType) (*EditSession, error) {
+	session := &EditSession{
+		encryptKey:     encryptKey,
+		originals:      make(map[string]editSessionValue),
+		plaintextPaths: make(map[string]bool),
+		secrets:        make(map[string]string),
+	}
+	var nodes []jsonStringValue
+	if err := walkJSONStringValues(data, func(node jsonStringValue) error {
+		if isEncryptedValueString(node.value) {
+			nodes = append(nodes, node)
+		} else {
+			session.plaintextPaths[node.path] = true
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	plaintexts := make([]string, len(nodes))
+	for i, node := range nodes {
+		ev, err := NewEncryptedValue(node.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse encrypted value at %q: %v", node.path, err)
+		}
+		if plaintexts[i], err = ev.Decrypt(decryptKey); err != nil {
+			return nil, fmt.Errorf("failed to decrypt value at %q: %v", node.path, err)
+		}
+		session.originals[node.path] = editSessionValue{
+			serialized: node.value,
+			plaintext:  plaintexts[i],
+		}
+		session.secrets[plaintexts[i]] = node.value
+	}
+	session.decrypted = replaceJSONStringValues(data, nodes, plaintexts)
+	return session, nil
+}
+
+// Decrypted returns the original document with every encrypted value replaced with its plaintext. This is the content
+// that should be edited. The returned document contains plaintext secrets and must be handled accordingly.
+func (s *EditSession) Decrypted() []byte {
+	return append([]byte(nil), s.decrypted...)
+}
+
+// Reencrypt returns the result of re-encrypting the provided edited version of the document returned by Decrypted.
+// String values that are already encrypted (see DecryptAllInJSON) are not modified. Every other string value is handled
+// as follows:
+//
+//   - A value at a path that was encrypted in the original document is replaced with the original serialized value if
+//     its plaintext is unchanged, and with the result of encrypting it otherwise.
+//   - A value at a path that did not exist in the original document whose plaintext is the plaintext of an encrypted
+//     value in the original document (for example, because the value was moved) is replaced with the original
+//     serialized value for that plaintext.
+//   - All other values (including new values and values at paths that were not encrypted in the original document) are
+//     not modified, even if they are secrets or are equal to the plaintext of a secret: new secrets must be encrypted
+//     separately.
+//
+// Re-encrypting the unedited document returned by Decrypted therefore returns the original document exactly.
+//
+// All other content of the edited document is preserved exactly. Returns an error that identifies the JSON path of the
+// first value that cannot be encrypted.
+func (s *EditSession) Reencrypt(edited []byte) ([]byte, error) {
+	encrypter := s.encryptKey.Type.AlgorithmType().Encrypter()
+	var nodes []jsonStringValue
+	var replacements []string
+	if err := walkJSONStringValues(edited, func(node jsonStringValue) error {
+		if isEncryptedValueString(node.value) {
+			return nil
+		}
+		original, wasEncrypted := s.originals[node.path]
+		switch {
+		case wasEncrypted && original.plaintext == node.value:
+			replacements = append(replacements, original.serialized)
+		case wasEncrypted:
+			ev, err := encrypter.Encrypt(node.value, s.encryptKey)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt value at %q: %v", node.path, err)
+			}
+			replacements = append(replacements, string(ev.ToSerializable()))
+		case s.plaintextPaths[node.path]:
+			return nil
+		default:
+			serialized, ok := s.secrets[node.value]
+			if !ok {
+				return nil
+			}
+			replacements = append(replacements, serialized)
+		}
+		nodes = append(nodes, node)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return replaceJSONStringValues(edited, nodes, replacements), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditSession(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypt := func(plaintext string) string {
+		ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt(plaintext, kp.EncryptionKey)
+		require.NoError(t, err)
+		return string(ev.ToSerializable())
+	}
+	userEnc, passwordEnc, tokenEnc := encrypt("admin"), encrypt("hunter2"), encrypt("abc")
+	original := fmt.Sprintf(`{
+  "user": %q,
+  "password": %q,
+  "tokens": [%q],
+  "host": "db"
+}`, userEnc, passwordEnc, tokenEnc)
+
+	session, err := encryptedconfigvalue.NewEditSession([]byte(original), kp.DecryptionKey, kp.EncryptionKey)
+	require.NoError(t, err)
+	decrypted := string(session.Decrypted())
+	assert.Equal(t, `{
+  "user": "admin",
+  "password": "hunter2",
+  "tokens": ["abc"],
+  "host": "db"
+}`, decrypted)
+
+	// re-encrypting an unedited document restores the original exactly
+	reencrypted, err := session.Reencrypt([]byte(decrypted))
+	require.NoError(t, err)
+	assert.Equal(t, original, string(reencrypted))
+
+	// change the password, insert a token before the existing one and change the host
+	edited := `{
+  "user": "admin",
+  "password": "correct horse",
+  "tokens": ["new", "abc"],
+  "host": "db.internal"
+}`
+	reencrypted, err = session.Reencrypt([]byte(edited))
+	require.NoError(t, err)
+
+	var values struct {
+		User     string   `json:"user"`
+		Password string   `json:"password"`
+		Tokens   []string `json:"tokens"`
+		Host     string   `json:"host"`
+	}
+	require.NoError(t, json.Unmarshal(reencrypted, &values))
+	assert.Equal(t, userEnc, values.User)
+	assert.True(t, strings.HasPrefix(values.Password, "enc:"))
+	assert.NotEqual(t, passwordEnc, values.Password)
+	// value at a previously encrypted path is encrypted and the moved value keeps its original serialized form
+	assert.True(t, strings.HasPrefix(values.Tokens[0], "enc:"))
+	assert.Equal(t, tokenEnc, values.Tokens[1])
+	assert.Equal(t, "db.internal", values.Host)
+
+	decryptedAgain, err := encryptedconfigvalue.DecryptAllInJSON(reencrypted, kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(decryptedAgain))
+}
+
+func TestEditSessionPlaintextEqualToSecret(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt("db", kp.EncryptionKey)
+	require.NoError(t, err)
+	original := fmt.Sprintf(`{"host": "db", "pw": %q}`, ev.ToSerializable())
+
+	session, err := encryptedconfigvalue.NewEditSession([]byte(original), kp.DecryptionKey, kp.EncryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, `{"host": "db", "pw": "db"}`, string(session.Decrypted()))
+
+	// the non-secret value that equals the plaintext of a secret is not encrypted
+	reencrypted, err := session.Reencrypt(session.Decrypted())
+	require.NoError(t, err)
+	assert.Equal(t, original, string(reencrypted))
+}
+
+func TestEditSessionError(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.NewEditSession([]byte(fmt.Sprintf(`{"a": %q}`, testAESEncryptedVal)), kp.DecryptionKey, kp.EncryptionKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decrypt value at "/a"`)
+}
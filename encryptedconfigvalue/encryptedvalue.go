@@ -15,8 +15,13 @@ import (
 // "enc:<base64-encoded-encrypted-value>".
 type SerializedEncryptedValue string
 
-func newSerializedEncryptedValue(bytes []byte) SerializedEncryptedValue {
-	return SerializedEncryptedValue(fmt.Sprintf(encPrefix + base64.StdEncoding.EncodeToString(bytes)))
+// newSerializedEncryptedValueBytes returns the bytes of the serialized form of an encrypted value with the provided
+// content, which is "enc:<base64-encoded-content>".
+func newSerializedEncryptedValueBytes(content []byte) []byte {
+	out := make([]byte, len(encPrefix)+base64.StdEncoding.EncodedLen(len(content)))
+	copy(out, encPrefix)
+	base64.StdEncoding.Encode(out[len(encPrefix):], content)
+	return out
 }
 
 // EncryptedValue represents a value that has been encrypted using encrypted-config-value. The value can be decrypted
@@ -29,16 +34,41 @@ type EncryptedValue interface {
 	// an error is encountered during decryption.
 	Decrypt(key KeyWithType) (string, error)
 
+	// DecryptValidUTF8 decrypts this value in the same manner as Decrypt, but returns ErrInvalidUTF8 if the decrypted
+	// value is not valid UTF-8. It should be used for values whose plaintext is expected to be text, so that a value
+	// that contains binary data (for example, because the wrong secret was encrypted) is detected when it is decrypted.
+	DecryptValidUTF8(key KeyWithType) (string, error)
+
 	// ToSerializable returns the string that can be used to serialize this EncryptedValue. The returned string can
 	// be used as input to the "NewEncryptedValue" function to recreate the value. The serialized string is of the
 	// form "enc:<base64-encoded-content>". The exact content that is base64-encoded is dependent on the concrete
 	// implementation. A valid EncryptedValue must be able to successfully generate a serializable form of itself --
 	// that is, for any valid EncryptedValue, this call must succeed and produce valid output.
 	ToSerializable() SerializedEncryptedValue
+
+	// ToSerializableBytes returns the bytes of the string returned by ToSerializable. It is equivalent to
+	// []byte(ToSerializable()), but avoids converting between strings and byte slices when writing the serialized
+	// value. Returns an error if the value cannot be serialized, which indicates a bug in the implementation.
+	ToSerializableBytes() ([]byte, error)
+
+	// KeyHint returns the key hint stored with this value and true if this value has a key hint, or an empty string
+	// and false otherwise. A key hint is a human-readable note (for example, "prod-kms") that indicates which key should
+	// be used to decrypt the value. Key hints are advisory only: they are not covered by the encryption, so anyone who
+	// can modify the serialized value can change or remove its key hint without affecting decryption. Key hints must
+	// never be trusted for security decisions.
+	KeyHint() (string, bool)
 }
 
 const encPrefix = "enc:"
 
+// isEncryptedValueString returns true if the provided string has the prefix of a serialized encrypted value: "enc:",
+// "encc:" or "encs:". Functions that decrypt the values in a document use this to find them, so values of the form
+// "encs:..." (which NewEncryptedValue rejects because their signature must be verified using VerifyAndParse) cause an
+// error rather than being passed through as if they were plaintext.
+func isEncryptedValueString(s string) bool {
+	return strings.HasPrefix(s, encPrefix) || strings.HasPrefix(s, encCBORPrefix) || strings.HasPrefix(s, encSignedPrefix)
+}
+
 // MustNewEncryptedValueFromSerialized returns the result of calling NewEncryptedValueFromSerialized with the provided
 // arguments. Panics if the call returns an error. This function should only be used when instantiating values that are
 // known to be formatted correctly.
@@ -71,9 +101,35 @@ func MustNewEncryptedValue(evStr string) EncryptedValue {
 //
 // If the decoded <base64-text> is valid JSON, this function treats it as a new format value; otherwise, it decodes it
 // as a legacy format value.
+//
+// Values of the form "encc:<base64-text>", where the <base64-text> encodes a CBOR representation of the EncryptedValue
+// (as returned by ToSerializableCBOR), are also supported. Values are parsed using CBORCodec if they are of the form
+// "encc:..." and using DefaultCodec otherwise.
+//
+// Serialized values may have a checksum suffix (as returned by ToSerializableChecksummed), such as
+// "enc:<base64-text>#<crc32>". If the value has a checksum suffix, the checksum is verified before the value is parsed
+// and ErrChecksumMismatch is returned if it does not match.
 func NewEncryptedValue(evStr string) (EncryptedValue, error) {
+	evStr, err := stripChecksum(evStr)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(evStr, encCBORPrefix) {
+		return CBORCodec().Unmarshal([]byte(evStr))
+	}
+	if strings.HasPrefix(evStr, encSignedPrefix) {
+		return nil, fmt.Errorf(`signed encrypted values of the form "%s..." must be parsed using VerifyAndParse`, encSignedPrefix)
+	}
+	return DefaultCodec().Unmarshal([]byte(evStr))
+}
+
+// newEncryptedValueFromJSONForm creates a new encrypted value from its serialized representation of the form
+// "enc:<base64-text>", where the decoded <base64-text> is either its JSON representation or, for values in the legacy
+// format, its ciphertext.
+func newEncryptedValueFromJSONForm(evStr string) (EncryptedValue, error) {
 	if !strings.HasPrefix(evStr, encPrefix) {
-		return nil, fmt.Errorf(`encrypted value must be of the form "%s...", was: %q`, encPrefix, evStr)
+		// the input is not included in the error because it may be a plaintext value
+		return nil, fmt.Errorf(`encrypted value must be of the form "%s..." or "%s..."`, encPrefix, encCBORPrefix)
 	}
 
 	contentB64 := evStr[len(encPrefix):]
@@ -94,19 +150,33 @@ func NewEncryptedValue(evStr string) (EncryptedValue, error) {
 	if err := json.Unmarshal(evContentBytes, &evWrapper); err != nil {
 		return nil, err
 	}
+	if parsed, ok := evWrapper.val.(parsedJSONValue); ok {
+		parsed.setParsedJSONBytes(evContentBytes)
+	}
 	return evWrapper.val, nil
 }
 
-func encryptedValToSerializable(ev EncryptedValue) SerializedEncryptedValue {
+// encryptedValToSerializableBytes returns the serialized form of an EncryptedValue whose content is its JSON
+// representation.
+func encryptedValToSerializableBytes(ev EncryptedValue) ([]byte, error) {
 	jsonBytes, err := json.Marshal(ev)
 	if err != nil {
-		// part of the contract of EncryptedValue is that it must be safe to JSON-serialize.
+		return nil, fmt.Errorf("json.Marshal for EncryptedValue of type %T failed: %v", ev, err)
+	}
+	return newSerializedEncryptedValueBytes(jsonBytes), nil
+}
+
+// toSerializable returns the result of ToSerializableBytes for the provided value as a SerializedEncryptedValue.
+func toSerializable(ev EncryptedValue) SerializedEncryptedValue {
+	serialized, err := ev.ToSerializableBytes()
+	if err != nil {
+		// part of the contract of EncryptedValue is that it must be safe to serialize.
 		// If an error occurs here, it is considered a programmer error.
-		panic(fmt.Errorf("json.Marshal for EncryptedValue of type %T failed: this should never occur, "+
+		panic(fmt.Errorf("serializing EncryptedValue of type %T failed: this should never occur, "+
 			"and indicates that there is a bug in the implementation. Please file an issue on the go-encrypted-config-value project. "+
 			"Error: %v", ev, err))
 	}
-	return newSerializedEncryptedValue(jsonBytes)
+	return SerializedEncryptedValue(serialized)
 }
 
 type encryptedValWrapper struct {
@@ -116,6 +186,7 @@ type encryptedValWrapper struct {
 func (ev *encryptedValWrapper) UnmarshalJSON(data []byte) error {
 	val := struct {
 		Algorithm AlgorithmType `json:"type"`
+		Mode      string        `json:"mode"`
 	}{}
 	if err := json.Unmarshal(data, &val); err != nil {
 		return err
@@ -125,17 +196,39 @@ func (ev *encryptedValWrapper) UnmarshalJSON(data []byte) error {
 	default:
 		return fmt.Errorf("unrecognized algorithm type: %s", val.Algorithm)
 	case AES:
+		if val.Mode == gcmSegmentedMode {
+			var aesVal aesGCMSegmentedEncryptedValue
+			if err := json.Unmarshal(data, &aesVal); err != nil {
+				return err
+			}
+			evWrapper.val = &aesVal
+			break
+		}
 		var aesVal aesGCMEncryptedValue
 		if err := json.Unmarshal(data, &aesVal); err != nil {
 			return err
 		}
 		evWrapper.val = &aesVal
 	case RSA:
+		if val.Mode == envelopeMode {
+			var envelopeVal rsaEnvelopeEncryptedValue
+			if err := json.Unmarshal(data, &envelopeVal); err != nil {
+				return err
+			}
+			evWrapper.val = &envelopeVal
+			break
+		}
 		var rsaVal rsaOAEPEncryptedValue
 		if err := json.Unmarshal(data, &rsaVal); err != nil {
 			return err
 		}
 		evWrapper.val = &rsaVal
+	case THRESHOLD:
+		var thresholdVal thresholdEncryptedValue
+		if err := json.Unmarshal(data, &thresholdVal); err != nil {
+			return err
+		}
+		evWrapper.val = &thresholdVal
 	}
 	*ev = evWrapper
 	return nil
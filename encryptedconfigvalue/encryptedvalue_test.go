@@ -73,3 +73,20 @@ func TestDecryptEncryptedValue(t *testing.T) {
 		assert.Equal(t, currCase.plaintext, decrypted, "Case %d: %s", i, currCase.name)
 	}
 }
+
+func TestToSerializableBytes(t *testing.T) {
+	for i, currCase := range []encryptedconfigvalue.SerializedEncryptedValue{
+		testAESEncryptedVal,
+		testRSAEncryptedVal,
+		javaLegacyAESEncryptedVal,
+		javaLegacyRSAEncryptedVal,
+	} {
+		ev, err := encryptedconfigvalue.NewEncryptedValueFromSerialized(currCase)
+		require.NoError(t, err, "Case %d", i)
+
+		serialized, err := ev.ToSerializableBytes()
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, string(currCase), string(serialized), "Case %d", i)
+		assert.Equal(t, ev.ToSerializable(), encryptedconfigvalue.SerializedEncryptedValue(serialized), "Case %d", i)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+const (
+	envelopeMode                   = "ENVELOPE"
+	defaultEnvelopeDataKeySizeBits = 256
+)
+
+type rsaEnvelopeEncrypter struct {
+	rsaOAEPCipher   *encryption.RSAOAEPCipher
+	dataKeySizeBits int
+}
+
+// NewRSAEnvelopeEncrypter returns an encrypter that encrypts values using envelope encryption with a 256-bit AES data
+// key. It is equivalent to RSAEnvelopeEncrypterWithDataKeySize(256).
+func NewRSAEnvelopeEncrypter() Encrypter {
+	return RSAEnvelopeEncrypterWithDataKeySize(defaultEnvelopeDataKeySizeBits)
+}
+
+// RSAEnvelopeEncrypterWithDataKeySize returns an encrypter that encrypts values using envelope encryption: each value
+// is encrypted using AES-GCM (with encrypted-config-value's standard AES parameters) with a freshly generated data key
+// of the provided size, and the data key is encrypted using RSA-OAEP (with encrypted-config-value's standard RSA
+// parameters) with the provided RSA public key. Unlike values created by NewRSAOAEPEncrypter, the size of the
+// plaintext is not limited by the size of the RSA key. The data key size must be 128, 192 or 256 bits: Encrypt returns
+// an error for any other size. The returned EncryptedValue will be serialized in the new format, and is decrypted
+// using the RSA private key.
+func RSAEnvelopeEncrypterWithDataKeySize(dataKeyBits int) Encrypter {
+	return &rsaEnvelopeEncrypter{
+		rsaOAEPCipher:   encryption.RSAOAEPCipherWithAlgorithms(rsaOAEPDefaultOAEPHash, rsaOAEPDefaultMDF1Hash),
+		dataKeySizeBits: dataKeyBits,
+	}
+}
+
+func (r *rsaEnvelopeEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	if err := validateEnvelopeDataKeySize(r.dataKeySizeBits); err != nil {
+		return nil, err
+	}
+	dataKey, err := NewAESKey(r.dataKeySizeBits)
+	if err != nil {
+		return nil, err
+	}
+	dataKeyBytes := dataKey.Key.(*encryption.AESKey).Bytes()
+	defer zeroBytes(dataKeyBytes)
+
+	payload, err := NewAESGCMEncrypter().Encrypt(input, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := r.rsaOAEPCipher.Encrypt(dataKeyBytes, key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %v", err)
+	}
+	return &rsaEnvelopeEncryptedValue{
+		wrappedKey:  wrappedKey,
+		oaepHashAlg: r.rsaOAEPCipher.OAEPHashAlg(),
+		mdf1HashAlg: r.rsaOAEPCipher.MDF1HashAlg(),
+		payload:     payload.(*aesGCMEncryptedValue),
+	}, nil
+}
+
+// ReEnvelope returns a copy of the provided envelope encrypted value (as created by the encrypter returned by
+// RSAEnvelopeEncrypterWithDataKeySize) whose payload is encrypted using a freshly generated AES data key of the
+// provided size, which is wrapped using encryptKey. The value is decrypted using decryptKey. The RSA-OAEP parameters
+// and key hint of the provided value are preserved. Returns an error if dataKeyBits is not 128, 192 or 256, if the
+// provided value is not an envelope encrypted value or if it cannot be decrypted using decryptKey.
+func ReEnvelope(ev EncryptedValue, decryptKey, encryptKey KeyWithType, dataKeyBits int) (EncryptedValue, error) {
+	if err := validateEnvelopeDataKeySize(dataKeyBits); err != nil {
+		return nil, err
+	}
+	envelopeEV, ok := ev.(*rsaEnvelopeEncryptedValue)
+	if !ok {
+		return nil, fmt.Errorf("value of type %T is not an envelope encrypted value", ev)
+	}
+	decrypted, err := envelopeEV.Decrypt(decryptKey)
+	if err != nil {
+		return nil, err
+	}
+	encrypter := &rsaEnvelopeEncrypter{
+		rsaOAEPCipher:   encryption.RSAOAEPCipherWithAlgorithms(envelopeEV.oaepHashAlg, envelopeEV.mdf1HashAlg),
+		dataKeySizeBits: dataKeyBits,
+	}
+	reenveloped, err := encrypter.Encrypt(decrypted, encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	reenveloped.(*rsaEnvelopeEncryptedValue).keyHint = envelopeEV.keyHint
+	return reenveloped, nil
+}
+
+// MigrateRSAToEnvelope returns the result of decrypting the provided RSA value (in the legacy format or the new format)
+// using the provided RSA private key and re-encrypting it using the encrypter returned by NewRSAEnvelopeEncrypter with
+// the public key of the provided private key, so the migrated value can be decrypted using the same private key. The key
+// hint of the provided value is preserved. Returns an error if the provided key is not an RSA private key, if the
+// provided value is not an RSA value or if it cannot be decrypted using the provided key.
+func MigrateRSAToEnvelope(ev EncryptedValue, privKey KeyWithType) (EncryptedValue, error) {
+	if privKey.Type != RSAPrivKey {
+		return nil, fmt.Errorf("key must be an RSA private key, was %s", privKey.Type)
+	}
+	switch ev.(type) {
+	case *rsaOAEPEncryptedValue, *legacyEncryptedValue:
+	default:
+		return nil, fmt.Errorf("value of type %T is not an RSA encrypted value", ev)
+	}
+	decrypted, err := ev.Decrypt(privKey)
+	if err != nil {
+		return nil, err
+	}
+	migrated, err := NewRSAEnvelopeEncrypter().Encrypt(decrypted, encryptionKeyOf(privKey))
+	if err != nil {
+		return nil, err
+	}
+	migrated.(*rsaEnvelopeEncryptedValue).keyHint, _ = ev.KeyHint()
+	return migrated, nil
+}
+
+// EncryptWithDataKey returns an envelope encrypted value (of the form created by the encrypter returned by
+// RSAEnvelopeEncrypterWithDataKeySize) whose payload is the result of encrypting the provided plaintext using AES-GCM
+// with the provided data key, and whose wrapped data key is the provided wrapped data key, which is stored verbatim.
+// This allows the data key to be generated and wrapped externally (for example, by a hardware security module): no
+// data key is generated, and the provided data key is not stored or modified.
+//
+// The provided algorithm is the algorithm that was used to wrap the data key. Only RSA is supported, in which case the
+// data key must have been wrapped using RSA-OAEP with SHA-256 as the OAEP and MGF1 hash (encrypted-config-value's
+// standard RSA parameters), and the returned value is decrypted using the RSA private key that can unwrap it. The
+// wrapped data key cannot be verified without that private key, so a value created using a wrapped data key that does
+// not correspond to the provided data key fails to decrypt. Returns an error if the algorithm is not RSA or if the
+// data key is not 128, 192 or 256 bits.
+func EncryptWithDataKey(plaintext string, dataKey []byte, wrappedDataKey []byte, alg AlgorithmType) (EncryptedValue, error) {
+	if alg != RSA {
+		return nil, fmt.Errorf("data keys wrapped using algorithm %s are not supported: only %s is supported", alg, RSA)
+	}
+	if err := validateEnvelopeDataKeySize(len(dataKey) * 8); err != nil {
+		return nil, err
+	}
+	if len(wrappedDataKey) == 0 {
+		return nil, fmt.Errorf("wrapped data key must not be empty")
+	}
+	payload, err := NewAESGCMEncrypter().Encrypt(plaintext, AESKeyFromBytes(dataKey))
+	if err != nil {
+		return nil, err
+	}
+	return &rsaEnvelopeEncryptedValue{
+		wrappedKey:  append([]byte{}, wrappedDataKey...),
+		oaepHashAlg: rsaOAEPDefaultOAEPHash,
+		mdf1HashAlg: rsaOAEPDefaultMDF1Hash,
+		payload:     payload.(*aesGCMEncryptedValue),
+	}, nil
+}
+
+func validateEnvelopeDataKeySize(dataKeyBits int) error {
+	switch dataKeyBits {
+	case 128, 192, 256:
+		return nil
+	default:
+		return fmt.Errorf("data key size must be 128, 192 or 256 bits, was %d", dataKeyBits)
+	}
+}
+
+type rsaEnvelopeEncryptedValue struct {
+	wrappedKey  []byte
+	oaepHashAlg encryption.HashAlgorithm
+	mdf1HashAlg encryption.HashAlgorithm
+	payload     *aesGCMEncryptedValue
+	keyHint     string
+	parsedJSON
+}
+
+// rsaEnvelopeEncryptedValueJSON is the JSON representation of an envelope encrypted value. The "ciphertext", "iv" and
+// "tag" fields are the fields of the AES-GCM encrypted payload, and "wrapped-key" is the RSA-OAEP encrypted data key.
+type rsaEnvelopeEncryptedValueJSON struct {
+	Type        string `json:"type"`
+	Mode        string `json:"mode"`
+	WrappedKey  string `json:"wrapped-key"`
+	OAEPHashAlg string `json:"oaep-alg"`
+	MDF1HashAlg string `json:"mdf1-alg"`
+	Ciphertext  string `json:"ciphertext"`
+	IV          string `json:"iv"`
+	Tag         string `json:"tag"`
+	KeyHint     string `json:"key_hint,omitempty"`
+}
+
+func (ev rsaEnvelopeEncryptedValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rsaEnvelopeEncryptedValueJSON{
+		Type:        string(RSA),
+		Mode:        envelopeMode,
+		WrappedKey:  base64.StdEncoding.EncodeToString(ev.wrappedKey),
+		OAEPHashAlg: string(ev.oaepHashAlg),
+		MDF1HashAlg: string(ev.mdf1HashAlg),
+		Ciphertext:  base64.StdEncoding.EncodeToString(ev.payload.encrypted),
+		IV:          base64.StdEncoding.EncodeToString(ev.payload.nonce),
+		Tag:         base64.StdEncoding.EncodeToString(ev.payload.tag),
+		KeyHint:     ev.keyHint,
+	})
+}
+
+func (ev *rsaEnvelopeEncryptedValue) UnmarshalJSON(data []byte) error {
+	var evJSON rsaEnvelopeEncryptedValueJSON
+	if err := json.Unmarshal(data, &evJSON); err != nil {
+		return err
+	}
+	if evJSON.Mode != envelopeMode {
+		return fmt.Errorf("unsupported mode: expected %q, but was %q", envelopeMode, evJSON.Mode)
+	}
+
+	var decoded [4][]byte
+	for i, field := range []string{evJSON.WrappedKey, evJSON.Ciphertext, evJSON.IV, evJSON.Tag} {
+		var err error
+		if decoded[i], err = base64.StdEncoding.DecodeString(field); err != nil {
+			return err
+		}
+	}
+	oaepHashAlg := encryption.HashAlgorithm(evJSON.OAEPHashAlg)
+	if !isSupportedHashAlgorithm(oaepHashAlg) {
+		return fmt.Errorf("unrecognized hash algorithm %q specified as OAEP hash algorithm", evJSON.OAEPHashAlg)
+	}
+	mdf1HashAlg := encryption.HashAlgorithm(evJSON.MDF1HashAlg)
+	if !isSupportedHashAlgorithm(mdf1HashAlg) {
+		return fmt.Errorf("unrecognized hash algorithm %q specified as MDF1 hash algorithm", evJSON.MDF1HashAlg)
+	}
+
+	*ev = rsaEnvelopeEncryptedValue{
+		wrappedKey:  decoded[0],
+		oaepHashAlg: oaepHashAlg,
+		mdf1HashAlg: mdf1HashAlg,
+		payload: &aesGCMEncryptedValue{
+			encrypted: decoded[1],
+			nonce:     decoded[2],
+			tag:       decoded[3],
+		},
+		keyHint: evJSON.KeyHint,
+	}
+	return nil
+}
+
+func (ev *rsaEnvelopeEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	cipher := encryption.RSAOAEPCipherWithAlgorithms(ev.oaepHashAlg, ev.mdf1HashAlg)
+	dataKeyBytes, err := cipher.Decrypt(ev.wrappedKey, key.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %v", err)
+	}
+	defer zeroBytes(dataKeyBytes)
+	if err := validateEnvelopeDataKeySize(len(dataKeyBytes) * 8); err != nil {
+		return "", fmt.Errorf("invalid data key: %v", err)
+	}
+	return ev.payload.Decrypt(AESKeyFromBytes(dataKeyBytes))
+}
+
+func (ev *rsaEnvelopeEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
+func (ev *rsaEnvelopeEncryptedValue) ToSerializable() SerializedEncryptedValue {
+	return toSerializable(ev)
+}
+
+func (ev *rsaEnvelopeEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return encryptedValToSerializableBytes(ev)
+}
+
+func (ev *rsaEnvelopeEncryptedValue) KeyHint() (string, bool) {
+	return ev.keyHint, ev.keyHint != ""
+}
+
+func (ev *rsaEnvelopeEncryptedValue) setKeyHint(hint string) {
+	ev.keyHint = hint
+	ev.parsedJSON = parsedJSON{}
+}
+
+func (ev *rsaEnvelopeEncryptedValue) algorithm() AlgorithmType {
+	return RSA
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/palantir/go-encrypted-config-value/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSAEnvelopeEncrypter(t *testing.T) {
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// plaintext is larger than can be encrypted directly using RSA-OAEP with the key
+	plaintext := strings.Repeat("secret", 1000)
+	for i, dataKeyBits := range []int{128, 192, 256} {
+		ev, err := encryptedconfigvalue.RSAEnvelopeEncrypterWithDataKeySize(dataKeyBits).Encrypt(plaintext, rsaKP.EncryptionKey)
+		require.NoError(t, err, "Case %d", i)
+		assert.NoError(t, encryptedconfigvalue.RequireAlgorithm(ev, encryptedconfigvalue.RSA), "Case %d", i)
+
+		ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+		require.NoError(t, err, "Case %d", i)
+		decrypted, err := ev.Decrypt(rsaKP.DecryptionKey)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, plaintext, decrypted, "Case %d", i)
+	}
+
+	_, err = encryptedconfigvalue.RSAEnvelopeEncrypterWithDataKeySize(64).Encrypt(plaintext, rsaKP.EncryptionKey)
+	assert.EqualError(t, err, "data key size must be 128, 192 or 256 bits, was 64")
+}
+
+func TestReEnvelope(t *testing.T) {
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	newRSAKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	ev, err := encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.NewRSAEnvelopeEncrypter(), "prod").Encrypt("secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+	sizeOf := func(ev encryptedconfigvalue.EncryptedValue) int {
+		return len(ev.ToSerializable())
+	}
+
+	reenveloped, err := encryptedconfigvalue.ReEnvelope(ev, rsaKP.DecryptionKey, newRSAKP.EncryptionKey, 128)
+	require.NoError(t, err)
+	decrypted, err := reenveloped.Decrypt(newRSAKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+	_, err = reenveloped.Decrypt(rsaKP.DecryptionKey)
+	assert.Error(t, err)
+	hint, _ := reenveloped.KeyHint()
+	assert.Equal(t, "prod", hint)
+	// the wrapped key has the size of the RSA key regardless of the size of the data key
+	assert.Equal(t, sizeOf(ev), sizeOf(reenveloped))
+
+	for i, currCase := range []struct {
+		name        string
+		ev          encryptedconfigvalue.EncryptedValue
+		decryptKey  encryptedconfigvalue.KeyWithType
+		dataKeyBits int
+		wantErr     string
+	}{
+		{
+			name:        "invalid data key size",
+			ev:          ev,
+			decryptKey:  rsaKP.DecryptionKey,
+			dataKeyBits: 512,
+			wantErr:     "data key size must be 128, 192 or 256 bits, was 512",
+		},
+		{
+			name:        "value is not an envelope encrypted value",
+			ev:          encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testRSAEncryptedVal),
+			decryptKey:  encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testRSAEncryptedValPrivKey),
+			dataKeyBits: 128,
+			wantErr:     "value of type *encryptedconfigvalue.rsaOAEPEncryptedValue is not an envelope encrypted value",
+		},
+		{
+			name:        "wrong decryption key",
+			ev:          ev,
+			decryptKey:  newRSAKP.DecryptionKey,
+			dataKeyBits: 128,
+			wantErr:     "failed to unwrap data key: crypto/rsa: decryption error",
+		},
+	} {
+		_, err := encryptedconfigvalue.ReEnvelope(currCase.ev, currCase.decryptKey, newRSAKP.EncryptionKey, currCase.dataKeyBits)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestMigrateRSAToEnvelope(t *testing.T) {
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	legacyEV, err := encryptedconfigvalue.LegacyRSAOAEPEncrypter().Encrypt("legacy secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+	hintedEV, err := encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.RSA.Encrypter(), "prod").Encrypt("secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name      string
+		ev        encryptedconfigvalue.EncryptedValue
+		key       encryptedconfigvalue.KeyWithType
+		plaintext string
+		hint      string
+	}{
+		{
+			name:      "legacy RSA value",
+			ev:        legacyEV,
+			key:       rsaKP.DecryptionKey,
+			plaintext: "legacy secret",
+		},
+		{
+			name:      "RSA value with key hint",
+			ev:        hintedEV,
+			key:       rsaKP.DecryptionKey,
+			plaintext: "secret",
+			hint:      "prod",
+		},
+		{
+			name:      "Java legacy RSA value",
+			ev:        encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyRSAEncryptedVal),
+			key:       encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaRSALegacyPrivKey),
+			plaintext: javaPlaintext,
+		},
+	} {
+		migrated, err := encryptedconfigvalue.MigrateRSAToEnvelope(currCase.ev, currCase.key)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		migrated, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(migrated.ToSerializable())
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Contains(t, mustDecodeContent(t, migrated), `"mode":"ENVELOPE"`, "Case %d: %s", i, currCase.name)
+		decrypted, err := migrated.Decrypt(currCase.key)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.plaintext, decrypted, "Case %d: %s", i, currCase.name)
+		hint, _ := migrated.KeyHint()
+		assert.Equal(t, currCase.hint, hint, "Case %d: %s", i, currCase.name)
+	}
+
+	_, err = encryptedconfigvalue.MigrateRSAToEnvelope(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), rsaKP.DecryptionKey)
+	assert.EqualError(t, err, "value of type *encryptedconfigvalue.aesGCMEncryptedValue is not an RSA encrypted value")
+	_, err = encryptedconfigvalue.MigrateRSAToEnvelope(legacyEV, rsaKP.EncryptionKey)
+	assert.EqualError(t, err, "key must be an RSA private key, was RSA-PUB")
+}
+
+func mustDecodeContent(t *testing.T, ev encryptedconfigvalue.EncryptedValue) string {
+	content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(ev.ToSerializable()), "enc:"))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestEncryptWithDataKey(t *testing.T) {
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	otherRSAKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// the data key is generated and wrapped externally
+	dataKey, err := encryption.RandomBytes(32)
+	require.NoError(t, err)
+	originalDataKey := append([]byte{}, dataKey...)
+	wrappedDataKey, err := encryption.RSAOAEPCipherWithAlgorithms(encryption.SHA256, encryption.SHA256).Encrypt(dataKey, rsaKP.EncryptionKey.Key)
+	require.NoError(t, err)
+
+	ev, err := encryptedconfigvalue.EncryptWithDataKey("secret", dataKey, wrappedDataKey, encryptedconfigvalue.RSA)
+	require.NoError(t, err)
+	assert.Equal(t, originalDataKey, dataKey)
+	assert.Contains(t, mustDecodeContent(t, ev), fmt.Sprintf(`"wrapped-key":%q`, base64.StdEncoding.EncodeToString(wrappedDataKey)))
+
+	ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+	require.NoError(t, err)
+	decrypted, err := ev.Decrypt(rsaKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", decrypted)
+	_, err = ev.Decrypt(otherRSAKP.DecryptionKey)
+	assert.Error(t, err)
+
+	_, err = encryptedconfigvalue.EncryptWithDataKey("secret", dataKey, wrappedDataKey, encryptedconfigvalue.AES)
+	assert.EqualError(t, err, "data keys wrapped using algorithm AES are not supported: only RSA is supported")
+	_, err = encryptedconfigvalue.EncryptWithDataKey("secret", dataKey[:8], wrappedDataKey, encryptedconfigvalue.RSA)
+	assert.EqualError(t, err, "data key size must be 128, 192 or 256 bits, was 64")
+	_, err = encryptedconfigvalue.EncryptWithDataKey("secret", dataKey, nil, encryptedconfigvalue.RSA)
+	assert.EqualError(t, err, "wrapped data key must not be empty")
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorsDoNotContainSecrets verifies that errors returned across failure modes never contain the plaintext of the
+// value being decrypted or any of the key material that was provided.
+func TestErrorsDoNotContainSecrets(t *testing.T) {
+	const plaintext = "TOP-SECRET-PLAINTEXT-7f3a9c"
+
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	otherAESKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	otherRSAKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	segmentedEncrypter, err := encryptedconfigvalue.NewAESGCMSegmentedEncrypter(8)
+	require.NoError(t, err)
+
+	encrypt := func(encrypter encryptedconfigvalue.Encrypter, key encryptedconfigvalue.KeyWithType) encryptedconfigvalue.EncryptedValue {
+		ev, err := encrypter.Encrypt(plaintext, key)
+		require.NoError(t, err)
+		return ev
+	}
+	aesEV := encrypt(encryptedconfigvalue.AES.Encrypter(), aesKP.EncryptionKey)
+	rsaEV := encrypt(encryptedconfigvalue.RSA.Encrypter(), rsaKP.EncryptionKey)
+	legacyAESEV := encrypt(encryptedconfigvalue.LegacyAESGCMEncrypter(), aesKP.EncryptionKey)
+	legacyRSAEV := encrypt(encryptedconfigvalue.LegacyRSAOAEPEncrypter(), rsaKP.EncryptionKey)
+	segmentedEV := encrypt(segmentedEncrypter, aesKP.EncryptionKey)
+
+	// secrets contains every value that must never appear in an error
+	secrets := []string{plaintext}
+	for _, key := range []encryptedconfigvalue.KeyWithType{
+		aesKP.EncryptionKey,
+		otherAESKP.EncryptionKey,
+		rsaKP.EncryptionKey,
+		rsaKP.DecryptionKey,
+		otherRSAKP.DecryptionKey,
+	} {
+		serialized := string(key.ToSerializable())
+		secrets = append(secrets, serialized, serialized[strings.Index(serialized, ":")+1:])
+	}
+	aesKeyMaterial := string(aesKP.EncryptionKey.ToSerializable())[len("AES:"):]
+
+	decryptFn := func(ev encryptedconfigvalue.EncryptedValue, key encryptedconfigvalue.KeyWithType) func() error {
+		return func() error {
+			_, err := ev.Decrypt(key)
+			return err
+		}
+	}
+	parseAndDecryptFn := func(serialized string, key encryptedconfigvalue.KeyWithType) func() error {
+		return func() error {
+			ev, err := encryptedconfigvalue.NewEncryptedValue(serialized)
+			if err != nil {
+				return err
+			}
+			_, err = ev.Decrypt(key)
+			return err
+		}
+	}
+	newKeyFn := func(input string) func() error {
+		return func() error {
+			_, err := encryptedconfigvalue.NewKeyWithType(input)
+			return err
+		}
+	}
+	// corrupt flips a byte near the middle of the base64 content of a serialized value
+	corrupt := func(ev encryptedconfigvalue.EncryptedValue) string {
+		rawContent, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(ev.ToSerializable()), "enc:"))
+		require.NoError(t, err)
+		rawContent[len(rawContent)/2] ^= 0xff
+		return "enc:" + base64.StdEncoding.EncodeToString(rawContent)
+	}
+	truncate := func(ev encryptedconfigvalue.EncryptedValue) string {
+		serialized := string(ev.ToSerializable())
+		return serialized[:len(serialized)/2]
+	}
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("AES:"+plaintext), 0600))
+
+	for i, currCase := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"AES value with wrong AES key", decryptFn(aesEV, otherAESKP.EncryptionKey)},
+		{"AES value with RSA key", decryptFn(aesEV, rsaKP.DecryptionKey)},
+		{"RSA value with wrong RSA key", decryptFn(rsaEV, otherRSAKP.DecryptionKey)},
+		{"RSA value with public key", decryptFn(rsaEV, rsaKP.EncryptionKey)},
+		{"RSA value with AES key", decryptFn(rsaEV, aesKP.EncryptionKey)},
+		{"legacy AES value with wrong AES key", decryptFn(legacyAESEV, otherAESKP.EncryptionKey)},
+		{"legacy RSA value with wrong RSA key", decryptFn(legacyRSAEV, otherRSAKP.DecryptionKey)},
+		{"segmented value with wrong AES key", decryptFn(segmentedEV, otherAESKP.EncryptionKey)},
+		{"corrupt AES value", parseAndDecryptFn(corrupt(aesEV), aesKP.EncryptionKey)},
+		{"corrupt RSA value", parseAndDecryptFn(corrupt(rsaEV), rsaKP.DecryptionKey)},
+		{"corrupt legacy AES value", parseAndDecryptFn(corrupt(legacyAESEV), aesKP.EncryptionKey)},
+		{"corrupt segmented value", parseAndDecryptFn(corrupt(segmentedEV), aesKP.EncryptionKey)},
+		{"truncated AES value", parseAndDecryptFn(truncate(aesEV), aesKP.EncryptionKey)},
+		{"truncated legacy AES value", parseAndDecryptFn("enc:"+base64.StdEncoding.EncodeToString([]byte(plaintext[:8])), aesKP.EncryptionKey)},
+		{"plaintext provided as value", parseAndDecryptFn(plaintext, aesKP.EncryptionKey)},
+		{"plaintext provided as value content", parseAndDecryptFn("enc:"+plaintext, aesKP.EncryptionKey)},
+		{"key without type", newKeyFn(aesKeyMaterial)},
+		{"key with unknown type", newKeyFn("UNKNOWN:" + aesKeyMaterial)},
+		{"key with plaintext as type", newKeyFn(aesKeyMaterial + ":" + aesKeyMaterial)},
+		{"key with invalid content", newKeyFn("AES:" + plaintext)},
+		{"key with wrong length", newKeyFn("AES:" + aesKeyMaterial[:len(aesKeyMaterial)/2])},
+		{"RSA key with AES key material", newKeyFn("RSA-PRIV:" + aesKeyMaterial)},
+		{"string variable", func() error {
+			_, err := encryptedconfigvalue.DecryptSingleEncryptedValueStringVarString(plaintext, aesKP.EncryptionKey)
+			return err
+		}},
+		{"string variable with wrong key", func() error {
+			_, err := encryptedconfigvalue.DecryptSingleEncryptedValueStringVarString(fmt.Sprintf("${%s}", aesEV.ToSerializable()), otherAESKP.EncryptionKey)
+			return err
+		}},
+		{"JSON document with wrong key", func() error {
+			_, err := encryptedconfigvalue.DecryptAllInJSON([]byte(fmt.Sprintf(`{"a": "%s"}`, aesEV.ToSerializable())), otherAESKP.EncryptionKey)
+			return err
+		}},
+		{"file key source with invalid key", func() error {
+			_, err := encryptedconfigvalue.FileKeySource(keyFile).Resolve()
+			return err
+		}},
+	} {
+		var err error
+		require.NotPanics(t, func() {
+			err = currCase.fn()
+		}, "Case %d: %s", i, currCase.name)
+		require.Error(t, err, "Case %d: %s", i, currCase.name)
+		for _, secret := range secrets {
+			assert.NotContains(t, err.Error(), secret, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
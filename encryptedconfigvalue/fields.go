@@ -0,0 +1,41 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// algorithmTypeToJSONForm maps each algorithm type to the JSON representation of the values created by its default
+// Encrypter.
+var algorithmTypeToJSONForm = map[AlgorithmType]reflect.Type{
+	AES:       reflect.TypeOf(aesGCMEncryptedValueJSON{}),
+	RSA:       reflect.TypeOf(rsaOAEPEncryptedValueJSON{}),
+	THRESHOLD: reflect.TypeOf(thresholdEncryptedValueJSON{}),
+}
+
+// RequiredFields returns the names of the fields that are required in the JSON representation (the content of the
+// "enc:..." form) of values of the provided algorithm in document order, for example "type", "mode", "ciphertext",
+// "iv" and "tag" for AES. The fields are those of values in the mode of the default Encrypter for the algorithm
+// (GCM for AES and OAEP for RSA). Optional fields (such as "key_hint") are not included. The returned fields are
+// determined from the definition of the JSON representation, so they always match the fields that are written by
+// the Encrypter. Returns an error if the provided algorithm is not recognized.
+func RequiredFields(alg AlgorithmType) ([]string, error) {
+	jsonForm, ok := algorithmTypeToJSONForm[alg]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized algorithm type: %s", alg)
+	}
+	var fields []string
+	for i := 0; i < jsonForm.NumField(); i++ {
+		name, opts, _ := strings.Cut(jsonForm.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || strings.Contains(","+opts+",", ",omitempty,") {
+			continue
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
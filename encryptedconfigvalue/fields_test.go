@@ -0,0 +1,48 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredFields(t *testing.T) {
+	for i, currCase := range []struct {
+		alg  encryptedconfigvalue.AlgorithmType
+		ev   encryptedconfigvalue.SerializedEncryptedValue
+		want []string
+	}{
+		{encryptedconfigvalue.AES, testAESEncryptedVal, []string{"type", "mode", "ciphertext", "iv", "tag"}},
+		{encryptedconfigvalue.RSA, testRSAEncryptedVal, []string{"type", "mode", "ciphertext", "oaep-alg", "mdf1-alg"}},
+	} {
+		got, err := encryptedconfigvalue.RequiredFields(currCase.alg)
+		require.NoError(t, err, "Case %d: %s", i, currCase.alg)
+		assert.Equal(t, currCase.want, got, "Case %d: %s", i, currCase.alg)
+
+		// the required fields are exactly the fields of a value that was written by the default encrypter
+		content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(currCase.ev), "enc:"))
+		require.NoError(t, err, "Case %d: %s", i, currCase.alg)
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(content, &fields), "Case %d: %s", i, currCase.alg)
+		assert.Len(t, fields, len(got), "Case %d: %s", i, currCase.alg)
+		for _, field := range got {
+			assert.Contains(t, fields, field, "Case %d: %s", i, currCase.alg)
+		}
+	}
+
+	got, err := encryptedconfigvalue.RequiredFields(encryptedconfigvalue.THRESHOLD)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"type", "threshold", "shares", "payload"}, got)
+
+	_, err = encryptedconfigvalue.RequiredFields("DES")
+	assert.EqualError(t, err, "unrecognized algorithm type: DES")
+}
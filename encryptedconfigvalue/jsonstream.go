@@ -0,0 +1,89 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bufio"
+	"io"
+)
+
+// DecryptAllInJSONStream behaves like DecryptAllInJSON, but reads the JSON document from the provided reader and writes
+// the result to the provided writer incrementally as the document is read. The amount of memory used is bounded by the
+// size of the largest single token in the document (rather than the size of the whole document), and deeply nested
+// documents are supported. If an error occurs, the output that precedes the failing value may already have been
+// written to the writer.
+func DecryptAllInJSONStream(r io.Reader, w io.Writer, key KeyWithType) error {
+	return transformJSONStream(r, w, func(node jsonStringValue) (string, bool, error) {
+		if !isEncryptedValueString(node.value) {
+			return "", false, nil
+		}
+		decrypted, err := decryptJSONStringValue(node, key)
+		return decrypted, true, err
+	})
+}
+
+// transformJSONStream copies the JSON document from the provided reader to the provided writer, calling transform for
+// every string value in document order. If transform returns true, the value is replaced with the JSON string encoding
+// of the returned replacement. All other content of the document is copied exactly.
+func transformJSONStream(r io.Reader, w io.Writer, transform func(node jsonStringValue) (replacement string, replace bool, err error)) error {
+	rec := &jsonStreamRecorder{
+		r: r,
+	}
+	bw := bufio.NewWriter(w)
+	if err := walkJSON(rec, rec.tokenStart, func(node jsonStringValue) error {
+		replacement, replace, err := transform(node)
+		if err != nil || !replace {
+			return err
+		}
+		if _, err := bw.Write(rec.take(node.start)); err != nil {
+			return err
+		}
+		if _, err := bw.Write(jsonStringBytes(replacement)); err != nil {
+			return err
+		}
+		rec.take(node.end)
+		return nil
+	}, func(offset int64) error {
+		_, err := bw.Write(rec.take(offset))
+		return err
+	}); err != nil {
+		return err
+	}
+	// write any trailing content (such as a final newline) that follows the last token
+	if _, err := bw.Write(rec.buf); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// jsonStreamRecorder is an io.Reader that records the bytes read from the underlying reader until they are consumed
+// using take.
+type jsonStreamRecorder struct {
+	r io.Reader
+	// buf contains the bytes of the document starting at offset base that have been read but not yet taken.
+	buf  []byte
+	base int64
+}
+
+func (r *jsonStreamRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf = append(r.buf, p[:n]...)
+	return n, err
+}
+
+// take returns the recorded bytes up to the provided document offset and discards them. The returned slice is only
+// valid until the next call to Read or take.
+func (r *jsonStreamRecorder) take(until int64) []byte {
+	n := until - r.base
+	out := r.buf[:n]
+	r.buf = r.buf[n:]
+	r.base = until
+	return out
+}
+
+// tokenStart returns the document offset of the first byte of the token that follows the provided offset.
+func (r *jsonStreamRecorder) tokenStart(offset int64) int64 {
+	return r.base + jsonTokenStart(r.buf, offset-r.base)
+}
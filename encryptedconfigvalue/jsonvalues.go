@@ -0,0 +1,302 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DecryptAllInJSON returns a copy of the provided JSON document in which every string value of the form "enc:..." or
+// "encc:..." is replaced with the result of decrypting it using the provided key. String values of the form "encs:..."
+// are signed values that must be verified using VerifyAndParse, so an error is returned for them. Object keys are never
+// modified. All content of the document other than the replaced string values (including whitespace, key order and
+// number formatting) is preserved exactly. Returns an error that identifies the JSON path (in JSON Pointer form, for
+// example "/db/password") of the first value that cannot be parsed or decrypted.
+func DecryptAllInJSON(data []byte, key KeyWithType) ([]byte, error) {
+	return DecryptAllInJSONConcurrent(data, key, 1)
+}
+
+// DecryptAllInJSONConcurrent behaves like DecryptAllInJSON, but decrypts the encrypted values in the document in
+// parallel using the specified number of workers. If workers is less than 1, runtime.GOMAXPROCS(0) workers are used.
+// If multiple values fail to decrypt, the returned error is the one for the value that occurs first in the document.
+func DecryptAllInJSONConcurrent(data []byte, key KeyWithType, workers int) ([]byte, error) {
+	nodes, err := encryptedJSONStringValues(data)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	decrypted := make([]string, len(nodes))
+	errs := make([]error, len(nodes))
+	var failed int32
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				decrypted[idx], errs[idx] = decryptJSONStringValue(nodes[idx], key)
+				if errs[idx] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	// indices are dispatched in document order and dispatching stops after the first failure, so every value that
+	// precedes a failed value is guaranteed to have been processed.
+	for idx := range nodes {
+		if atomic.LoadInt32(&failed) != 0 {
+			break
+		}
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return replaceJSONStringValues(data, nodes, decrypted), nil
+}
+
+// DecryptAllInJSONPerPath behaves like DecryptAllInJSON, but decrypts every encrypted value using the key returned by
+// keyForPath for the JSON path (in JSON Pointer form) of the value. This allows different parts of a single document to
+// be decrypted using different keys. If keyForPath returns the zero value of KeyWithType for a path, no key is available
+// for the value: if failOnMissingKey is true, an error is returned; otherwise, the value is left encrypted.
+func DecryptAllInJSONPerPath(data []byte, keyForPath func(path string) KeyWithType, failOnMissingKey bool) ([]byte, error) {
+	nodes, err := encryptedJSONStringValues(data)
+	if err != nil {
+		return nil, err
+	}
+	var decryptedNodes []jsonStringValue
+	var decrypted []string
+	for _, node := range nodes {
+		key := keyForPath(node.path)
+		if key.Key == nil {
+			if failOnMissingKey {
+				return nil, fmt.Errorf("no key is available for encrypted value at %q", node.path)
+			}
+			continue
+		}
+		decryptedValue, err := decryptJSONStringValue(node, key)
+		if err != nil {
+			return nil, err
+		}
+		decryptedNodes = append(decryptedNodes, node)
+		decrypted = append(decrypted, decryptedValue)
+	}
+	return replaceJSONStringValues(data, decryptedNodes, decrypted), nil
+}
+
+func decryptJSONStringValue(node jsonStringValue, key KeyWithType) (string, error) {
+	ev, err := NewEncryptedValue(node.value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse encrypted value at %q: %v", node.path, err)
+	}
+	decrypted, err := ev.Decrypt(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value at %q: %v", node.path, err)
+	}
+	return decrypted, nil
+}
+
+// jsonStringValue is a string value (not an object key) that occurs in a JSON document.
+type jsonStringValue struct {
+	// path is the JSON Pointer (RFC 6901) for the value.
+	path string
+	// start is the offset of the opening quote of the value in the document.
+	start int64
+	// end is the offset just past the closing quote of the value in the document.
+	end int64
+	// value is the decoded string value.
+	value string
+}
+
+// encryptedJSONStringValues returns all of the string values in the provided JSON document for which
+// isEncryptedValueString returns true in document order.
+func encryptedJSONStringValues(data []byte) ([]jsonStringValue, error) {
+	var nodes []jsonStringValue
+	if err := walkJSONStringValues(data, func(node jsonStringValue) error {
+		if isEncryptedValueString(node.value) {
+			nodes = append(nodes, node)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// jsonFrame tracks the state of a JSON object or array that is being walked.
+type jsonFrame struct {
+	isObject  bool
+	expectKey bool
+	key       string
+	index     int
+}
+
+// walkJSONStringValues calls visit for every string value in the provided JSON document in document order. Object keys
+// are not visited.
+func walkJSONStringValues(data []byte, visit func(jsonStringValue) error) error {
+	return walkJSON(bytes.NewReader(data), func(offset int64) int64 {
+		return jsonTokenStart(data, offset)
+	}, visit, nil)
+}
+
+// walkJSON reads the JSON document from the provided reader and calls visit for every string value in document order.
+// tokenStart must return the offset of the first byte of the token that follows the provided offset in the document.
+// If afterToken is non-nil, it is called with the offset just past every token after the token has been processed.
+// Containers are tracked using an explicit stack, so the depth of the document is not limited by the call stack.
+func walkJSON(r io.Reader, tokenStart func(offset int64) int64, visit func(jsonStringValue) error, afterToken func(offset int64) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var stack []*jsonFrame
+	prevOffset := dec.InputOffset()
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if len(stack) > 0 || prevOffset == 0 {
+				return fmt.Errorf("invalid JSON: %v", io.ErrUnexpectedEOF)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %v", err)
+		}
+		offset := dec.InputOffset()
+		if stack, err = walkJSONToken(stack, tok, func(path string, value string) error {
+			return visit(jsonStringValue{
+				path:  path,
+				start: tokenStart(prevOffset),
+				end:   offset,
+				value: value,
+			})
+		}); err != nil {
+			return err
+		}
+		if afterToken != nil {
+			if err := afterToken(offset); err != nil {
+				return err
+			}
+		}
+		prevOffset = offset
+	}
+}
+
+// walkJSONToken updates the provided stack to reflect the provided token and returns the updated stack. If the token
+// is a string value (rather than an object key), visitString is called with its JSON Pointer and value.
+func walkJSONToken(stack []*jsonFrame, tok json.Token, visitString func(path string, value string) error) ([]*jsonFrame, error) {
+	var top *jsonFrame
+	if len(stack) > 0 {
+		top = stack[len(stack)-1]
+	}
+
+	if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 && stack[len(stack)-1].isObject {
+			stack[len(stack)-1].expectKey = true
+		}
+		return stack, nil
+	}
+
+	if top != nil && top.isObject && top.expectKey {
+		// token is an object key: decoder guarantees that it is a string
+		top.key = tok.(string)
+		top.expectKey = false
+		return stack, nil
+	}
+
+	// token is the start of a value
+	if top != nil && !top.isObject {
+		top.index++
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		return append(stack, &jsonFrame{
+			isObject:  v == '{',
+			expectKey: v == '{',
+			index:     -1,
+		}), nil
+	case string:
+		if err := visitString(jsonPointer(stack), v); err != nil {
+			return nil, err
+		}
+	}
+	if top != nil && top.isObject {
+		top.expectKey = true
+	}
+	return stack, nil
+}
+
+// jsonTokenStart returns the offset of the first byte of the token that follows the provided offset. The bytes between
+// two tokens can only be whitespace or the ',' and ':' separators (which json.Decoder consumes as part of Token).
+func jsonTokenStart(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// jsonPointer returns the JSON Pointer (RFC 6901) for the current position of the provided stack.
+func jsonPointer(stack []*jsonFrame) string {
+	var sb strings.Builder
+	for _, frame := range stack {
+		sb.WriteByte('/')
+		if frame.isObject {
+			sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(frame.key))
+		} else {
+			sb.WriteString(strconv.Itoa(frame.index))
+		}
+	}
+	return sb.String()
+}
+
+// replaceJSONStringValues returns a copy of data in which the provided nodes, which must be in document order, are
+// replaced with the JSON string encoding of the corresponding replacement.
+func replaceJSONStringValues(data []byte, nodes []jsonStringValue, replacements []string) []byte {
+	var buf bytes.Buffer
+	var prevEnd int64
+	for i, node := range nodes {
+		buf.Write(data[prevEnd:node.start])
+		buf.Write(jsonStringBytes(replacements[i]))
+		prevEnd = node.end
+	}
+	buf.Write(data[prevEnd:])
+	return buf.Bytes()
+}
+
+// jsonStringBytes returns the JSON encoding of the provided string. Unlike json.Marshal, HTML characters are not
+// escaped.
+func jsonStringBytes(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		// encoding a string never fails
+		panic(err)
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
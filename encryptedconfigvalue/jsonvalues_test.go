@@ -0,0 +1,243 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptAllInJSON(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+
+	for i, currCase := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "top-level string",
+			input: fmt.Sprintf(`"%s"`, testAESEncryptedVal),
+			want:  `"plaintext"`,
+		},
+		{
+			name: "nested values with formatting preserved",
+			input: fmt.Sprintf(`{
+  "b": 1.50,
+  "a": {"password": "%s", "enc:key": "x"},
+  "list": [ "plain", "%s" ]
+}`, testAESEncryptedVal, testAESEncryptedVal),
+			want: `{
+  "b": 1.50,
+  "a": {"password": "plaintext", "enc:key": "x"},
+  "list": [ "plain", "plaintext" ]
+}`,
+		},
+		{
+			name:  "no encrypted values",
+			input: `{"a": ["b", {"c": null}], "d": true}`,
+			want:  `{"a": ["b", {"c": null}], "d": true}`,
+		},
+	} {
+		got, err := encryptedconfigvalue.DecryptAllInJSON([]byte(currCase.input), key)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, string(got), "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestDecryptAllInJSONCBORAndSignedValues(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	cborVal, err := encryptedconfigvalue.ToSerializableCBOR(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	require.NoError(t, err)
+	input := fmt.Sprintf(`{"json": "%s", "cbor": ["%s"]}`, testAESEncryptedVal, cborVal)
+	want := `{"json": "plaintext", "cbor": ["plaintext"]}`
+
+	decrypted, err := encryptedconfigvalue.DecryptAllInJSON([]byte(input), key)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(decrypted))
+
+	var out bytes.Buffer
+	require.NoError(t, encryptedconfigvalue.DecryptAllInJSONStream(strings.NewReader(input), &out, key))
+	assert.Equal(t, want, out.String())
+
+	// signed values must be verified, so they are not passed through as plaintext
+	_, signKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signed, err := encryptedconfigvalue.SignValue(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), signKey)
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.DecryptAllInJSON([]byte(fmt.Sprintf(`{"signed": "%s"}`, signed)), key)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to parse encrypted value at "/signed"`)
+}
+
+func TestDecryptAllInJSONConcurrent(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	var input, want strings.Builder
+	input.WriteString("[")
+	want.WriteString("[")
+	for i := 0; i < 100; i++ {
+		if i > 0 {
+			input.WriteString(",")
+			want.WriteString(",")
+		}
+		plaintext := fmt.Sprintf(`secret <%d> "quoted"`, i)
+		ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt(plaintext, kp.EncryptionKey)
+		require.NoError(t, err)
+		_, _ = fmt.Fprintf(&input, `{"id":%d,"secret":"%s"}`, i, ev.ToSerializable())
+		_, _ = fmt.Fprintf(&want, `{"id":%d,"secret":%q}`, i, plaintext)
+	}
+	input.WriteString("]")
+	want.WriteString("]")
+
+	for _, workers := range []int{0, 1, 8} {
+		got, err := encryptedconfigvalue.DecryptAllInJSONConcurrent([]byte(input.String()), kp.DecryptionKey, workers)
+		require.NoError(t, err, "workers: %d", workers)
+		assert.Equal(t, want.String(), string(got), "workers: %d", workers)
+	}
+}
+
+func TestDecryptAllInJSONErrors(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+
+	for i, currCase := range []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "invalid JSON",
+			input:   `{"a": `,
+			wantErr: "invalid JSON",
+		},
+		{
+			name:    "first failing path is reported",
+			input:   fmt.Sprintf(`{"ok": "%s", "a/b": [{"x~y": "enc:invalid"}], "c": "enc:invalid"}`, testAESEncryptedVal),
+			wantErr: `failed to parse encrypted value at "/a~1b/0/x~0y"`,
+		},
+		{
+			name:    "wrong key",
+			input:   fmt.Sprintf(`{"rsa": ["%s"]}`, testRSAEncryptedVal),
+			wantErr: `failed to decrypt value at "/rsa/0"`,
+		},
+	} {
+		for _, workers := range []int{1, 4} {
+			_, err := encryptedconfigvalue.DecryptAllInJSONConcurrent([]byte(currCase.input), key, workers)
+			require.Error(t, err, "Case %d: %s", i, currCase.name)
+			assert.Contains(t, err.Error(), currCase.wantErr, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestDecryptAllInJSONStream(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	const depth = 2000
+
+	for i, currCase := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "nested values with formatting preserved",
+			input: fmt.Sprintf(`{
+  "b": 1.50,
+  "a": {"password": "%s", "enc:key": "x"},
+  "list": [ "plain", "%s" ]
+}
+`, testAESEncryptedVal, testAESEncryptedVal),
+			want: `{
+  "b": 1.50,
+  "a": {"password": "plaintext", "enc:key": "x"},
+  "list": [ "plain", "plaintext" ]
+}
+`,
+		},
+		{
+			name:  "top-level number",
+			input: ` 12 `,
+			want:  ` 12 `,
+		},
+		{
+			name:  "deeply nested document",
+			input: strings.Repeat(`[{"a":`, depth) + fmt.Sprintf(`"%s"`, testAESEncryptedVal) + strings.Repeat(`}]`, depth),
+			want:  strings.Repeat(`[{"a":`, depth) + `"plaintext"` + strings.Repeat(`}]`, depth),
+		},
+	} {
+		// read one byte at a time to verify that tokens that span reads are handled
+		var out bytes.Buffer
+		err := encryptedconfigvalue.DecryptAllInJSONStream(iotest.OneByteReader(strings.NewReader(currCase.input)), &out, key)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, out.String(), "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestDecryptAllInJSONStreamErrors(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+
+	for i, currCase := range []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "invalid JSON",
+			input:   `{"a": `,
+			wantErr: "invalid JSON",
+		},
+		{
+			name:    "wrong key",
+			input:   fmt.Sprintf(`{"rsa": ["%s"]}`, testRSAEncryptedVal),
+			wantErr: `failed to decrypt value at "/rsa/0"`,
+		},
+	} {
+		err := encryptedconfigvalue.DecryptAllInJSONStream(strings.NewReader(currCase.input), io.Discard, key)
+		require.Error(t, err, "Case %d: %s", i, currCase.name)
+		assert.Contains(t, err.Error(), currCase.wantErr, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestDecryptAllInJSONPerPath(t *testing.T) {
+	aesKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	rsaKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testRSAEncryptedValPrivKey)
+	keyForPath := func(path string) encryptedconfigvalue.KeyWithType {
+		switch {
+		case strings.HasPrefix(path, "/tenants/a/"):
+			return aesKey
+		case strings.HasPrefix(path, "/tenants/b/"):
+			return rsaKey
+		default:
+			return encryptedconfigvalue.KeyWithType{}
+		}
+	}
+	input := fmt.Sprintf(`{"tenants": {"a": {"password": "%s"}, "b": {"password": "%s"}}, "shared": "%s"}`,
+		testAESEncryptedVal, testRSAEncryptedVal, testAESEncryptedVal)
+
+	got, err := encryptedconfigvalue.DecryptAllInJSONPerPath([]byte(input), keyForPath, false)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`{"tenants": {"a": {"password": "plaintext"}, "b": {"password": "plaintext"}}, "shared": "%s"}`,
+		testAESEncryptedVal), string(got))
+
+	_, err = encryptedconfigvalue.DecryptAllInJSONPerPath([]byte(input), keyForPath, true)
+	assert.EqualError(t, err, `no key is available for encrypted value at "/shared"`)
+
+	// key for the wrong tenant fails
+	_, err = encryptedconfigvalue.DecryptAllInJSONPerPath([]byte(input), func(path string) encryptedconfigvalue.KeyWithType {
+		return aesKey
+	}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decrypt value at "/tenants/b/password"`)
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// ErrKeychainUnavailable is returned (wrapped) when the keychain of the current platform cannot be accessed.
+var ErrKeychainUnavailable = errors.New("platform keychain is unavailable")
+
+// keychainLookup returns the secret stored in the platform keychain for the provided service and account. It is a
+// variable so that it can be replaced in tests.
+var keychainLookup = lookupKeychainItem
+
+// KeyFromKeychain returns a KeyWithType for the key stored in the keychain of the current platform under the provided
+// service and account names. The keychain item must contain the serialized form of the key as returned by
+// KeyWithType's ToSerializable function (for example, "AES:<base64-encoded-key>").
+//
+// The supported keychains are the macOS Keychain (read using the "security" tool), the Windows Credential Manager
+// (read from the generic credential with the target name "<service>:<account>") and, on other platforms, any
+// libsecret-compatible secret service (read using the "secret-tool" tool with the attributes "service" and
+// "account").
+//
+// The returned key does not hold the key material: the keychain is read every time the key is used (for example, by
+// every call to Decrypt), and the key material that is read is zeroed as soon as the operation that used it returns.
+// The keychain is also read once when this function is called to determine the type of the key and to verify that the
+// item exists. The key is never written to disk. Returns an error that wraps ErrKeychainUnavailable if the keychain of
+// the current platform cannot be accessed; operations that use the returned key return the same errors if the keychain
+// cannot be read when they are performed.
+func KeyFromKeychain(service, account string) (KeyWithType, error) {
+	key, err := readKeychainKey(service, account)
+	if err != nil {
+		return KeyWithType{}, err
+	}
+	zeroKey(key)
+	return KeyWithType{
+		Type: key.Type,
+		Key: &keychainKey{
+			service: service,
+			account: account,
+			keyType: key.Type,
+		},
+		Label: key.Label,
+	}, nil
+}
+
+// keychainKey is an encryption.KeyResolver for a key stored in the keychain of the current platform.
+type keychainKey struct {
+	service string
+	account string
+	keyType KeyType
+}
+
+// Bytes reads the key from the keychain and returns its bytes. Returns nil if the key cannot be read.
+func (k *keychainKey) Bytes() []byte {
+	key, release, err := k.ResolveKey()
+	if err != nil {
+		return nil
+	}
+	defer release()
+	return append([]byte(nil), key.Bytes()...)
+}
+
+// ResolveKey reads the key from the keychain. The returned release function zeroes the key material that was read.
+func (k *keychainKey) ResolveKey() (encryption.Key, func(), error) {
+	key, err := readKeychainKey(k.service, k.account)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key.Type != k.keyType {
+		zeroKey(key)
+		return nil, nil, fmt.Errorf("keychain item for service %q and account %q contains a key of type %s, expected %s", k.service, k.account, key.Type, k.keyType)
+	}
+	return key.Key, func() {
+		zeroKey(key)
+	}, nil
+}
+
+// readKeychainKey reads and parses the key stored in the keychain of the current platform under the provided service
+// and account names.
+func readKeychainKey(service, account string) (KeyWithType, error) {
+	secret, err := keychainLookup(service, account)
+	if err != nil {
+		return KeyWithType{}, err
+	}
+	defer zeroBytes(secret)
+
+	key, err := NewKeyWithType(string(bytes.TrimSpace(secret)))
+	if err != nil {
+		// the underlying error is not included because it may contain the content of the keychain item
+		return KeyWithType{}, fmt.Errorf("keychain item for service %q and account %q does not contain a valid serialized key", service, account)
+	}
+	return key.WithLabel(fmt.Sprintf("keychain item for service %q and account %q", service, account)), nil
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
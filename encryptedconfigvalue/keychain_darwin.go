@@ -0,0 +1,18 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+// securityItemNotFoundExitCode is the exit status of the "security" tool when the requested item does not exist
+// (errSecItemNotFound).
+const securityItemNotFoundExitCode = 44
+
+// lookupKeychainItem reads the generic password for the provided service and account from the macOS Keychain.
+func lookupKeychainItem(service, account string) ([]byte, error) {
+	return runKeychainCommand(service, account, isSecurityItemNotFound, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+}
+
+func isSecurityItemNotFound(exitCode int, stderr []byte) bool {
+	return exitCode == securityItemNotFoundExitCode
+}
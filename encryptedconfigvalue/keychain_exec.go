@@ -0,0 +1,42 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// runKeychainCommand runs the provided keychain command-line tool and returns its standard output. Returns an error
+// that wraps ErrKeychainUnavailable if the tool is not installed, and an error that wraps ErrKeyNotProvided if the tool
+// does not output anything or if it fails in a manner for which isNotFound returns true.
+func runKeychainCommand(service, account string, isNotFound func(exitCode int, stderr []byte) bool, tool string, args ...string) ([]byte, error) {
+	toolPath, err := exec.LookPath(tool)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q could not be found: %v", ErrKeychainUnavailable, tool, err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(toolPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if isNotFound(exitErr.ExitCode(), stderr.Bytes()) {
+				return nil, fmt.Errorf("%w: keychain item for service %q and account %q not found", ErrKeyNotProvided, service, account)
+			}
+			return nil, fmt.Errorf("failed to read keychain item for service %q and account %q: %s: %s", service, account, tool, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return nil, fmt.Errorf("%w: failed to run %q: %v", ErrKeychainUnavailable, tool, err)
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("%w: keychain item for service %q and account %q not found", ErrKeyNotProvided, service, account)
+	}
+	return stdout.Bytes(), nil
+}
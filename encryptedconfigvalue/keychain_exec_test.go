@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunKeychainCommand(t *testing.T) {
+	isNotFound := func(exitCode int, stderr []byte) bool {
+		return exitCode == 44
+	}
+	for i, currCase := range []struct {
+		name            string
+		script          string
+		want            string
+		wantErr         string
+		wantNotProvided bool
+	}{
+		{
+			name:   "item found",
+			script: "echo 'AES:key'",
+			want:   "AES:key\n",
+		},
+		{
+			name:            "item not found",
+			script:          "echo 'The specified item could not be found in the keychain.' >&2; exit 44",
+			wantErr:         `key not provided: keychain item for service "my-service" and account "my-account" not found`,
+			wantNotProvided: true,
+		},
+		{
+			name:            "no output",
+			script:          "exit 0",
+			wantErr:         `key not provided: keychain item for service "my-service" and account "my-account" not found`,
+			wantNotProvided: true,
+		},
+		{
+			name:    "other failure",
+			script:  "echo 'user interaction is not allowed' >&2; exit 36",
+			wantErr: `failed to read keychain item for service "my-service" and account "my-account": fake-keychain-tool: user interaction is not allowed`,
+		},
+	} {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "fake-keychain-tool"), []byte("#!/bin/sh\n"+currCase.script+"\n"), 0700), "Case %d: %s", i, currCase.name)
+		t.Setenv("PATH", dir)
+
+		got, err := runKeychainCommand("my-service", "my-account", isNotFound, "fake-keychain-tool")
+		if currCase.wantErr != "" {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, currCase.wantNotProvided, errors.Is(err, ErrKeyNotProvided), "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, string(got), "Case %d: %s", i, currCase.name)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !windows
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+)
+
+// lookupKeychainItem reads the secret with the provided service and account attributes from the libsecret secret
+// service.
+func lookupKeychainItem(service, account string) ([]byte, error) {
+	return runKeychainCommand(service, account, isSecretToolItemNotFound, "secret-tool", "lookup", "service", service, "account", account)
+}
+
+// isSecretToolItemNotFound returns true if the exit status and error output of "secret-tool lookup" indicate that the
+// requested item does not exist: the tool exits with status 1 without writing an error message in that case, while
+// other failures (such as the secret service being unavailable) are reported on standard error.
+func isSecretToolItemNotFound(exitCode int, stderr []byte) bool {
+	return exitCode == 1 && len(bytes.TrimSpace(stderr)) == 0
+}
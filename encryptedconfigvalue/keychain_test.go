@@ -0,0 +1,183 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFromKeychain(t *testing.T) {
+	origLookup := keychainLookup
+	defer func() {
+		keychainLookup = origLookup
+	}()
+
+	const serializedKey = "AES:LICx0yKzQm5a6IE13aJ3xOsRv+8AujqHocTFI4yk4Jw="
+	for i, currCase := range []struct {
+		name    string
+		lookup  func(service, account string) ([]byte, error)
+		wantErr string
+	}{
+		{
+			name: "valid key",
+			lookup: func(service, account string) ([]byte, error) {
+				if service != "my-service" || account != "my-account" {
+					return nil, fmt.Errorf("unexpected item %s/%s", service, account)
+				}
+				return []byte(serializedKey + "\n"), nil
+			},
+		},
+		{
+			name: "keychain unavailable",
+			lookup: func(service, account string) ([]byte, error) {
+				return nil, fmt.Errorf("%w: test", ErrKeychainUnavailable)
+			},
+			wantErr: "platform keychain is unavailable: test",
+		},
+		{
+			name: "invalid key content is not included in error",
+			lookup: func(service, account string) ([]byte, error) {
+				return []byte("not-a-key"), nil
+			},
+			wantErr: `keychain item for service "my-service" and account "my-account" does not contain a valid serialized key`,
+		},
+	} {
+		keychainLookup = currCase.lookup
+		key, err := KeyFromKeychain("my-service", "my-account")
+		if currCase.wantErr != "" {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, AESKey, key.Type, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, SerializedKeyWithType(serializedKey), key.ToSerializable(), "Case %d: %s", i, currCase.name)
+		assert.Equal(t, `keychain item for service "my-service" and account "my-account"`, key.Describe().Label, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestKeyFromKeychainReadsOnDemand(t *testing.T) {
+	origLookup := keychainLookup
+	defer func() {
+		keychainLookup = origLookup
+	}()
+
+	const serializedKey = "AES:LICx0yKzQm5a6IE13aJ3xOsRv+8AujqHocTFI4yk4Jw="
+	lookups := 0
+	var lookupErr error
+	var secrets [][]byte
+	keychainLookup = func(service, account string) ([]byte, error) {
+		lookups++
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		secret := []byte(serializedKey)
+		secrets = append(secrets, secret)
+		return secret, nil
+	}
+
+	key, err := KeyFromKeychain("my-service", "my-account")
+	require.NoError(t, err)
+	assert.Equal(t, 1, lookups)
+
+	ev, err := AES.Encrypter().Encrypt("secret", MustNewKeyWithType(serializedKey))
+	require.NoError(t, err)
+	for j := 0; j < 2; j++ {
+		decrypted, err := ev.Decrypt(key)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", decrypted)
+	}
+	decrypted, err := DecryptAllInJSON([]byte(fmt.Sprintf(`{"a": %q}`, ev.ToSerializable())), key)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": "secret"}`, string(decrypted))
+	// the keychain is read for every operation and the content that was read is zeroed
+	assert.Equal(t, 4, lookups)
+	for _, secret := range secrets {
+		assert.Equal(t, make([]byte, len(serializedKey)), secret)
+	}
+
+	lookupErr = fmt.Errorf("%w: locked", ErrKeychainUnavailable)
+	_, err = ev.Decrypt(key)
+	assert.True(t, errors.Is(err, ErrKeychainUnavailable))
+}
+
+func TestKeychainKeyProvider(t *testing.T) {
+	origLookup := keychainLookup
+	defer func() {
+		keychainLookup = origLookup
+	}()
+
+	const serializedKey = "AES:LICx0yKzQm5a6IE13aJ3xOsRv+8AujqHocTFI4yk4Jw="
+	lookups := 0
+	keychainLookup = func(service, account string) ([]byte, error) {
+		lookups++
+		return []byte(serializedKey), nil
+	}
+
+	provider := KeychainKeyProvider("my-service", "my-account")
+	// the keychain is not read until the key is needed
+	assert.Equal(t, 0, lookups)
+
+	var provided []KeyWithType
+	for j := 0; j < 2; j++ {
+		require.NoError(t, provider.WithKey(func(key KeyWithType) error {
+			assert.Equal(t, SerializedKeyWithType(serializedKey), key.ToSerializable())
+			provided = append(provided, key)
+			return nil
+		}))
+	}
+	// the keychain is read for every use and the key material is zeroed afterwards
+	assert.Equal(t, 2, lookups)
+	for _, key := range provided {
+		assert.Equal(t, make([]byte, 32), key.Key.Bytes())
+	}
+}
+
+func TestChainedKeySourceKeychainItemNotFound(t *testing.T) {
+	origLookup := keychainLookup
+	defer func() {
+		keychainLookup = origLookup
+	}()
+
+	const (
+		serializedKey = "AES:LICx0yKzQm5a6IE13aJ3xOsRv+8AujqHocTFI4yk4Jw="
+		envVar        = "ENCRYPTED_CONFIG_VALUE_TEST_KEY"
+	)
+	t.Setenv(envVar, serializedKey)
+
+	for i, currCase := range []struct {
+		name      string
+		lookupErr error
+		wantErr   string
+	}{
+		{
+			name:      "missing keychain item is skipped",
+			lookupErr: fmt.Errorf("%w: keychain item not found", ErrKeyNotProvided),
+		},
+		{
+			name:      "unreadable keychain item is not skipped",
+			lookupErr: errors.New("failed to read keychain item: user interaction is not allowed"),
+			wantErr:   "failed to read keychain item: user interaction is not allowed",
+		},
+	} {
+		keychainLookup = func(service, account string) ([]byte, error) {
+			return nil, currCase.lookupErr
+		}
+		key, err := ChainedKeySource(
+			KeychainKeySource("my-service", "my-account"),
+			EnvKeySource(envVar),
+		).Resolve()
+		if currCase.wantErr != "" {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, SerializedKeyWithType(serializedKey), key.ToSerializable(), "Case %d: %s", i, currCase.name)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modAdvapi32   = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW = modAdvapi32.NewProc("CredReadW")
+	procCredFree  = modAdvapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric = 1
+	errorNotFound   = syscall.Errno(1168)
+)
+
+// credential mirrors the Windows CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// lookupKeychainItem reads the blob of the generic credential with the target name "<service>:<account>" from the
+// Windows Credential Manager.
+func lookupKeychainItem(service, account string) ([]byte, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeychainUnavailable, err)
+	}
+	target, err := syscall.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keychain service %q or account %q: %v", service, account, err)
+	}
+
+	var cred *credential
+	if ret, _, callErr := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); ret == 0 {
+		if errors.Is(callErr, errorNotFound) {
+			return nil, fmt.Errorf("%w: keychain item for service %q and account %q not found", ErrKeyNotProvided, service, account)
+		}
+		return nil, fmt.Errorf("failed to read keychain item for service %q and account %q: %v", service, account, callErr)
+	}
+	defer func() {
+		_, _, _ = procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	}()
+
+	secret := make([]byte, cred.CredentialBlobSize)
+	copy(secret, unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize))
+	return secret, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+)
+
+// keyHintSetter is implemented by EncryptedValue implementations whose serialized form can store a key hint. Setting the
+// key hint discards the JSON from which the value was parsed (see InnerJSON), since that JSON no longer represents it.
+type keyHintSetter interface {
+	setKeyHint(hint string)
+}
+
+type keyHintEncrypter struct {
+	encrypter Encrypter
+	hint      string
+}
+
+// WithKeyHint returns an Encrypter that encrypts values using the provided encrypter and stores the provided key hint
+// in every EncryptedValue that it returns. The hint can be retrieved using the KeyHint method of the returned value.
+//
+// The key hint is stored in the clear and is not authenticated: it is advisory only and can be modified by anyone who
+// can modify the serialized value. Do not store sensitive information in the hint. Encrypting with the returned
+// Encrypter fails if the provided encrypter produces values in the legacy format, which does not support key hints.
+func WithKeyHint(encrypter Encrypter, hint string) Encrypter {
+	return &keyHintEncrypter{
+		encrypter: encrypter,
+		hint:      hint,
+	}
+}
+
+func (e *keyHintEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	ev, err := e.encrypter.Encrypt(input, key)
+	if err != nil {
+		return nil, err
+	}
+	setter, ok := ev.(keyHintSetter)
+	if !ok {
+		return nil, fmt.Errorf("encrypted value of type %T does not support key hints", ev)
+	}
+	setter.setKeyHint(e.hint)
+	return ev, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithKeyHint(t *testing.T) {
+	for i, currAlg := range []encryptedconfigvalue.AlgorithmType{
+		encryptedconfigvalue.AES,
+		encryptedconfigvalue.RSA,
+	} {
+		kp, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+
+		ev, err := encryptedconfigvalue.WithKeyHint(currAlg.Encrypter(), "prod-kms").Encrypt("secret", kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+
+		// key hint survives serialization round trip
+		ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		hint, ok := ev.KeyHint()
+		assert.True(t, ok, "Case %d: %s", i, currAlg)
+		assert.Equal(t, "prod-kms", hint, "Case %d: %s", i, currAlg)
+
+		decrypted, err := ev.Decrypt(kp.DecryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		assert.Equal(t, "secret", decrypted, "Case %d: %s", i, currAlg)
+	}
+}
+
+func TestKeyHintAbsent(t *testing.T) {
+	for i, currCase := range []encryptedconfigvalue.SerializedEncryptedValue{
+		testAESEncryptedVal,
+		testRSAEncryptedVal,
+		javaLegacyAESEncryptedVal,
+	} {
+		ev, err := encryptedconfigvalue.NewEncryptedValueFromSerialized(currCase)
+		require.NoError(t, err, "Case %d", i)
+		hint, ok := ev.KeyHint()
+		assert.False(t, ok, "Case %d", i)
+		assert.Equal(t, "", hint, "Case %d", i)
+		// values without a key hint serialize exactly as they did before key hints were supported
+		assert.Equal(t, currCase, ev.ToSerializable(), "Case %d", i)
+	}
+}
+
+func TestWithKeyHintLegacyEncrypter(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.LegacyAESGCMEncrypter(), "prod-kms").Encrypt("secret", kp.EncryptionKey)
+	assert.EqualError(t, err, "encrypted value of type *encryptedconfigvalue.legacyEncryptedValue does not support key hints")
+}
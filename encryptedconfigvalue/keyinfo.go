@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// KeyInfo is the non-secret metadata of a key, as returned by KeyWithType.Describe. It never contains key material, so
+// it is safe to log or to marshal as JSON.
+type KeyInfo struct {
+	// Label is the label of the key (see KeyWithType.Label). It is empty if the key is not labeled.
+	Label string `json:"label"`
+	// Type is the type of the key.
+	Type KeyType `json:"type"`
+	// Algorithm is the algorithm of the values that the key encrypts or decrypts.
+	Algorithm AlgorithmType `json:"algorithm"`
+	// SizeBits is the size of the key in bits: the length of the key for AES keys and the size of the modulus for RSA
+	// keys. It is 0 if the size is not known.
+	SizeBits int `json:"size-bits"`
+	// Fingerprint is the fingerprint of the key in the form "sha256:<hex-digest>". The fingerprint of an RSA private key
+	// is the fingerprint of its public key, so the keys of an RSA key pair have the same fingerprint. The fingerprint is
+	// the same as the one recorded in an AuditRecord for the key.
+	Fingerprint string `json:"fingerprint"`
+	// CanEncrypt is true if the key can be used to encrypt values.
+	CanEncrypt bool `json:"can-encrypt"`
+	// CanDecrypt is true if the key can be used to decrypt values.
+	CanDecrypt bool `json:"can-decrypt"`
+}
+
+// Describe returns the non-secret metadata of the key. Use WithLabel to name a key that is not provided by a KeySource
+// so that it can be identified in the returned KeyInfo.
+func (kwt KeyWithType) Describe() KeyInfo {
+	info := KeyInfo{
+		Label:     kwt.Label,
+		Type:      kwt.Type,
+		Algorithm: kwt.Type.AlgorithmType(),
+	}
+	kwt, release, err := resolveKeyWithType(kwt)
+	if err != nil {
+		return info
+	}
+	defer release()
+
+	info.Fingerprint = keyFingerprint(kwt)
+	switch k := kwt.Key.(type) {
+	case *encryption.AESKey:
+		info.SizeBits = len(k.Bytes()) * 8
+		info.CanEncrypt = true
+		info.CanDecrypt = true
+	case *encryption.RSAPublicKey:
+		info.SizeBits = k.N.BitLen()
+		info.CanEncrypt = true
+	case *encryption.RSAPrivateKey:
+		info.SizeBits = k.N.BitLen()
+		info.CanDecrypt = true
+	}
+	return info
+}
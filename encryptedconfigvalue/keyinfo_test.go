@@ -0,0 +1,98 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	aesKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name       string
+		key        encryptedconfigvalue.KeyWithType
+		wantType   encryptedconfigvalue.KeyType
+		wantAlg    encryptedconfigvalue.AlgorithmType
+		wantSize   int
+		canEncrypt bool
+		canDecrypt bool
+	}{
+		{
+			name:       "AES key",
+			key:        aesKey,
+			wantType:   encryptedconfigvalue.AESKey,
+			wantAlg:    encryptedconfigvalue.AES,
+			wantSize:   256,
+			canEncrypt: true,
+			canDecrypt: true,
+		},
+		{
+			name:       "RSA public key",
+			key:        rsaKP.EncryptionKey,
+			wantType:   encryptedconfigvalue.RSAPubKey,
+			wantAlg:    encryptedconfigvalue.RSA,
+			wantSize:   2048,
+			canEncrypt: true,
+		},
+		{
+			name:       "RSA private key",
+			key:        rsaKP.DecryptionKey,
+			wantType:   encryptedconfigvalue.RSAPrivKey,
+			wantAlg:    encryptedconfigvalue.RSA,
+			wantSize:   2048,
+			canDecrypt: true,
+		},
+	} {
+		info := currCase.key.Describe()
+		assert.Equal(t, currCase.wantType, info.Type, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.wantAlg, info.Algorithm, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.wantSize, info.SizeBits, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.canEncrypt, info.CanEncrypt, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.canDecrypt, info.CanDecrypt, "Case %d: %s", i, currCase.name)
+		assert.True(t, strings.HasPrefix(info.Fingerprint, "sha256:"), "Case %d: %s", i, currCase.name)
+		assert.Empty(t, info.Label, "Case %d: %s", i, currCase.name)
+
+		// the JSON form does not contain the key material
+		infoJSON, err := json.Marshal(info)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		serializedKey := string(currCase.key.ToSerializable())
+		assert.NotContains(t, string(infoJSON), serializedKey[strings.Index(serializedKey, ":")+1:], "Case %d: %s", i, currCase.name)
+	}
+
+	// the keys of an RSA key pair have the same fingerprint
+	assert.Equal(t, rsaKP.EncryptionKey.Describe().Fingerprint, rsaKP.DecryptionKey.Describe().Fingerprint)
+	assert.NotEqual(t, aesKey.Describe().Fingerprint, rsaKP.EncryptionKey.Describe().Fingerprint)
+}
+
+func TestDescribeLabel(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	labeled := key.WithLabel("payments-service")
+
+	info := labeled.Describe()
+	assert.Equal(t, "payments-service", info.Label)
+	assert.Equal(t, key.Describe().Fingerprint, info.Fingerprint)
+	assert.Empty(t, key.Describe().Label)
+
+	infoJSON, err := json.Marshal(info)
+	require.NoError(t, err)
+	assert.Contains(t, string(infoJSON), `"label":"payments-service"`)
+
+	// the label does not change the serialized form of the key or its use
+	assert.Equal(t, key.ToSerializable(), labeled.ToSerializable())
+	ev, err := encryptedconfigvalue.NewEncryptedValue(string(testAESEncryptedVal))
+	require.NoError(t, err)
+	decrypted, err := ev.Decrypt(labeled)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", decrypted)
+}
@@ -31,6 +31,16 @@ type KeyPair struct {
 type KeyWithType struct {
 	Type KeyType
 	Key  encryption.Key
+	// Label is an optional, non-secret name for the key that is reported by Describe. It is not part of the serialized
+	// form of the key. Keys provided by the KeySource implementations in this package are labeled with the location
+	// they were read from.
+	Label string
+}
+
+// WithLabel returns a copy of the key with the provided label.
+func (kwt KeyWithType) WithLabel(label string) KeyWithType {
+	kwt.Label = label
+	return kwt
 }
 
 // SerializedKeyWithType is the serialized string representation of a KeyWithType. It is a string of the form
@@ -83,7 +93,8 @@ func MustNewKeyWithType(input string) KeyWithType {
 func NewKeyWithType(input string) (KeyWithType, error) {
 	parts := strings.Split(input, ":")
 	if len(parts) != 2 {
-		return KeyWithType{}, fmt.Errorf("key must be of the form <algorithm>:<key in base64>, was: %s", input)
+		// the input is not included in the error because it may contain key material
+		return KeyWithType{}, fmt.Errorf("key must be of the form <algorithm>:<key in base64>")
 	}
 
 	keyBytes, err := base64.StdEncoding.DecodeString(parts[1])
@@ -106,7 +117,23 @@ func NewKeyWithType(input string) (KeyWithType, error) {
 
 	alg, err := ToKeyType(parts[0])
 	if err != nil {
-		return KeyWithType{}, err
+		// the error returned by ToKeyType is not used because it includes the input, which may contain key material
+		return KeyWithType{}, fmt.Errorf("key must be of the form <algorithm>:<key in base64>, where <algorithm> is a known key type")
 	}
 	return alg.Generator()(keyBytes)
 }
+
+// resolveKeyWithType returns a KeyWithType that holds the key material of the provided key along with a function that
+// releases it. If the Key of the provided key is an encryption.KeyResolver, its key material is loaded using
+// ResolveKey. Otherwise, the provided key is returned.
+func resolveKeyWithType(kwt KeyWithType) (KeyWithType, func(), error) {
+	resolver, ok := kwt.Key.(encryption.KeyResolver)
+	if !ok {
+		return kwt, func() {}, nil
+	}
+	key, release, err := resolver.ResolveKey()
+	if err != nil {
+		return KeyWithType{}, nil, err
+	}
+	return KeyWithType{Type: kwt.Type, Key: key, Label: kwt.Label}, release, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"math/big"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// KeyProvider provides temporary access to a key, which allows the key to be loaded only when it is needed and
+// released immediately afterwards rather than being held in memory for the lifetime of a process.
+type KeyProvider interface {
+	// WithKey loads the key, calls fn with it and then zeroes the key material. The key must not be used or retained
+	// after fn returns. Returns the error returned by fn, or an error if the key cannot be loaded (in which case fn
+	// is not called).
+	WithKey(fn func(key KeyWithType) error) error
+}
+
+// KeyProviderFunc is an adapter that allows an ordinary function to be used as a KeyProvider.
+type KeyProviderFunc func(fn func(key KeyWithType) error) error
+
+// WithKey returns the result of calling f with fn.
+func (f KeyProviderFunc) WithKey(fn func(key KeyWithType) error) error {
+	return f(fn)
+}
+
+// KeySourceProvider returns a KeyProvider that resolves the key using the provided source every time WithKey is called
+// and zeroes the key material of the resolved key after fn returns. The source must return a key that is owned by the
+// caller every time it is resolved (as all of the KeySource implementations in this package do): a source that returns
+// a key that is shared or cached must not be used, since the shared key would be zeroed. The key material of AES keys
+// and the private values of RSA private keys are zeroed. Zeroing is best-effort: copies of the key material that are
+// made outside of the key (for example, by the source or internally by the crypto/rsa package) are not zeroed.
+func KeySourceProvider(source KeySource) KeyProvider {
+	return KeyProviderFunc(func(fn func(key KeyWithType) error) error {
+		key, err := source.Resolve()
+		if err != nil {
+			return err
+		}
+		defer zeroKey(key)
+		return fn(key)
+	})
+}
+
+// KeychainKeyProvider returns a KeyProvider for the key stored in the keychain of the current platform under the
+// provided service and account names (see KeyFromKeychain for the supported keychains). The keychain is read every time
+// WithKey is called and the key material is zeroed after fn returns. Unlike KeyFromKeychain, the keychain is not read
+// when this function is called.
+func KeychainKeyProvider(service, account string) KeyProvider {
+	return KeySourceProvider(KeychainKeySource(service, account))
+}
+
+// DecryptWithProvider decrypts the provided value using the key provided by the provided KeyProvider. The key is only
+// available to the provider for the duration of the decryption. Returns an error if the provider cannot load the key
+// or if the value cannot be decrypted using it.
+func DecryptWithProvider(ev EncryptedValue, p KeyProvider) (string, error) {
+	var decrypted string
+	err := p.WithKey(func(key KeyWithType) error {
+		var err error
+		decrypted, err = ev.Decrypt(key)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return decrypted, nil
+}
+
+// zeroKey overwrites the key material of the provided key with zeros. Public keys are not modified.
+func zeroKey(key KeyWithType) {
+	switch k := key.Key.(type) {
+	case *encryption.AESKey:
+		zeroBytes(k.Bytes())
+	case *encryption.RSAPrivateKey:
+		zeroBigInt(k.D)
+		for _, prime := range k.Primes {
+			zeroBigInt(prime)
+		}
+		zeroBigInt(k.Precomputed.Dp)
+		zeroBigInt(k.Precomputed.Dq)
+		zeroBigInt(k.Precomputed.Qinv)
+	}
+}
+
+func zeroBigInt(x *big.Int) {
+	if x == nil {
+		return
+	}
+	words := x.Bits()
+	for i := range words {
+		words[i] = 0
+	}
+	x.SetInt64(0)
+}
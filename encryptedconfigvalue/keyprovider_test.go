@@ -0,0 +1,72 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/palantir/go-encrypted-config-value/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptWithProvider(t *testing.T) {
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaEV, err := encryptedconfigvalue.RSA.Encrypter().Encrypt("secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+	serializedRSAKey := rsaKP.DecryptionKey.ToSerializable()
+
+	for i, currCase := range []struct {
+		name          string
+		ev            encryptedconfigvalue.EncryptedValue
+		serializedKey encryptedconfigvalue.SerializedKeyWithType
+		want          string
+	}{
+		{"AES", encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), testAESEncryptedValKey, "plaintext"},
+		{"RSA", rsaEV, serializedRSAKey, "secret"},
+	} {
+		var resolved []encryptedconfigvalue.KeyWithType
+		provider := encryptedconfigvalue.KeySourceProvider(encryptedconfigvalue.KeySourceFunc(func() (encryptedconfigvalue.KeyWithType, error) {
+			key, err := encryptedconfigvalue.NewKeyWithTypeFromSerialized(currCase.serializedKey)
+			resolved = append(resolved, key)
+			return key, err
+		}))
+
+		for j := 0; j < 2; j++ {
+			decrypted, err := encryptedconfigvalue.DecryptWithProvider(currCase.ev, provider)
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, currCase.want, decrypted, "Case %d: %s", i, currCase.name)
+		}
+		// the key is resolved for every decryption and the resolved key is zeroed afterwards
+		require.Len(t, resolved, 2, "Case %d: %s", i, currCase.name)
+		for _, key := range resolved {
+			switch k := key.Key.(type) {
+			case *encryption.AESKey:
+				assert.Equal(t, make([]byte, len(k.Bytes())), k.Bytes(), "Case %d: %s", i, currCase.name)
+			case *encryption.RSAPrivateKey:
+				assert.Zero(t, k.D.Sign(), "Case %d: %s", i, currCase.name)
+			}
+		}
+	}
+}
+
+func TestDecryptWithProviderErrors(t *testing.T) {
+	ev := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+
+	_, err := encryptedconfigvalue.DecryptWithProvider(ev, encryptedconfigvalue.KeySourceProvider(encryptedconfigvalue.KeySourceFunc(func() (encryptedconfigvalue.KeyWithType, error) {
+		return encryptedconfigvalue.KeyWithType{}, errors.New("key unavailable")
+	})))
+	assert.EqualError(t, err, "key unavailable")
+
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.DecryptWithProvider(ev, encryptedconfigvalue.KeyProviderFunc(func(fn func(encryptedconfigvalue.KeyWithType) error) error {
+		return fn(otherKP.DecryptionKey)
+	}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrKeyNotProvided is returned (wrapped) by a KeySource that does not provide a key, for example because the flag or
+// environment variable that it reads is not set. It is distinct from the errors returned by a source that provides an
+// invalid key or that cannot be read.
+var ErrKeyNotProvided = errors.New("key not provided")
+
+// KeySource provides a KeyWithType from some location, such as a command-line flag, an environment variable, a file or
+// a key management service.
+type KeySource interface {
+	// Resolve returns the key provided by this source. Every call should return a new key that is owned by the
+	// caller, which may zero its key material once it is no longer needed (see KeySourceProvider). Returns an error
+	// that wraps ErrKeyNotProvided if the source does not provide a key, or another error if the key that it provides
+	// is not valid or cannot be read.
+	Resolve() (KeyWithType, error)
+}
+
+// KeySourceFunc is an adapter that allows an ordinary function to be used as a KeySource.
+type KeySourceFunc func() (KeyWithType, error)
+
+// Resolve returns the result of calling f.
+func (f KeySourceFunc) Resolve() (KeyWithType, error) {
+	return f()
+}
+
+// ChainedKeySource returns a KeySource that resolves the provided sources in order and returns the key provided by
+// the first source that resolves successfully. Sources after the first successful one are not resolved. A source is
+// only skipped if it does not provide a key (its error wraps ErrKeyNotProvided): if a source returns any other error,
+// such as the error for an invalid key, that error is returned immediately and the remaining sources are not resolved.
+// Returns an error that wraps ErrKeyNotProvided and contains the errors returned by all of the sources if none of them
+// provides a key.
+func ChainedKeySource(sources ...KeySource) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		if len(sources) == 0 {
+			return KeyWithType{}, fmt.Errorf("no key sources were provided")
+		}
+		var errMsgs []string
+		for _, source := range sources {
+			key, err := source.Resolve()
+			if err == nil {
+				return key, nil
+			}
+			if !errors.Is(err, ErrKeyNotProvided) {
+				return KeyWithType{}, err
+			}
+			errMsgs = append(errMsgs, err.Error())
+		}
+		return KeyWithType{}, fmt.Errorf("%w: none of the key sources provided a key: %s", ErrKeyNotProvided, strings.Join(errMsgs, "; "))
+	})
+}
+
+// FlagKeySource returns a KeySource that provides the key whose serialized form is the value of the flag with the
+// provided name. The value is read when the source is resolved, so the provided pointer can be the one returned by a
+// function such as flag.String before the flags are parsed. The source does not provide a key if the value is empty.
+func FlagKeySource(name string, value *string) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		if value == nil || *value == "" {
+			return KeyWithType{}, fmt.Errorf("%w: flag %q is not set", ErrKeyNotProvided, name)
+		}
+		return parseKeySourceValue(fmt.Sprintf("flag %q", name), []byte(*value))
+	})
+}
+
+// EnvKeySource returns a KeySource that provides the key whose serialized form is the value of the environment
+// variable with the provided name. The source does not provide a key if the variable is unset or empty.
+func EnvKeySource(name string) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		value := os.Getenv(name)
+		if value == "" {
+			return KeyWithType{}, fmt.Errorf("%w: environment variable %q is not set", ErrKeyNotProvided, name)
+		}
+		return parseKeySourceValue(fmt.Sprintf("environment variable %q", name), []byte(value))
+	})
+}
+
+// FileKeySource returns a KeySource that provides the key whose serialized form is the content of the file at the
+// provided path. Leading and trailing whitespace in the file is ignored. The source does not provide a key if the file
+// does not exist.
+func FileKeySource(path string) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return KeyWithType{}, fmt.Errorf("%w: key file %q does not exist", ErrKeyNotProvided, path)
+		}
+		if err != nil {
+			return KeyWithType{}, fmt.Errorf("failed to read key file: %v", err)
+		}
+		defer zeroBytes(content)
+		return parseKeySourceValue(fmt.Sprintf("key file %q", path), content)
+	})
+}
+
+// KeychainKeySource returns a KeySource that provides the key stored in the keychain of the current platform under the
+// provided service and account names (see KeyFromKeychain for the supported keychains). The keychain is read every time
+// the source is resolved. The source does not provide a key if the keychain does not contain the item.
+func KeychainKeySource(service, account string) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		return readKeychainKey(service, account)
+	})
+}
+
+// KMSClient is the interface that a key management service client must implement to be used with KMSKeySource.
+type KMSClient interface {
+	// FetchKey returns the serialized form of the key with the provided identifier (the output of KeyWithType's
+	// ToSerializable function).
+	FetchKey(keyID string) ([]byte, error)
+}
+
+// KMSKeySource returns a KeySource that provides the key with the provided identifier fetched using the provided key
+// management service client. The error returned by the client is wrapped, so a client can return an error that wraps
+// ErrKeyNotProvided to indicate that the service does not have the key.
+func KMSKeySource(client KMSClient, keyID string) KeySource {
+	return KeySourceFunc(func() (KeyWithType, error) {
+		content, err := client.FetchKey(keyID)
+		if err != nil {
+			return KeyWithType{}, fmt.Errorf("failed to fetch key %q from key management service: %w", keyID, err)
+		}
+		defer zeroBytes(content)
+		return parseKeySourceValue(fmt.Sprintf("key %q from key management service", keyID), content)
+	})
+}
+
+func parseKeySourceValue(description string, value []byte) (KeyWithType, error) {
+	key, err := NewKeyWithType(string(bytes.TrimSpace(value)))
+	if err != nil {
+		// the underlying error is not included because it may contain the key material
+		return KeyWithType{}, fmt.Errorf("%s does not contain a valid serialized key", description)
+	}
+	return key.WithLabel(description), nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKMSClient map[string]string
+
+func (c testKMSClient) FetchKey(keyID string) ([]byte, error) {
+	key, ok := c[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no such key", encryptedconfigvalue.ErrKeyNotProvided)
+	}
+	return []byte(key), nil
+}
+
+func TestChainedKeySource(t *testing.T) {
+	const (
+		flagKey = "AES:kHCJE62tlzYsP2eIxSnstSJVIMyk74+gCu/ImrhGMq8="
+		envKey  = "AES:LICx0yKzQm5a6IE13aJ3xOsRv+8AujqHocTFI4yk4Jw="
+		fileKey = "AES:0JlMK+vn1T8+d43NRp49xi35lA/NQVSTeowTw4iLw5M="
+		kmsKey  = "AES:jL+EV1ylmlj0TqW6ZlbjB/FOJDUSsc2YoofV7gd7Hms="
+		envVar  = "ENCRYPTED_CONFIG_VALUE_TEST_KEY"
+	)
+	keyFile := filepath.Join(t.TempDir(), "encrypted-config-value.key")
+	kms := testKMSClient{"my-key": kmsKey}
+
+	for i, currCase := range []struct {
+		name      string
+		flagValue string
+		envValue  string
+		fileValue string
+		want      encryptedconfigvalue.SerializedKeyWithType
+		wantLabel string
+	}{
+		{"flag takes precedence", flagKey, envKey, fileKey, flagKey, `flag "key"`},
+		{"environment variable used if flag is not set", "", envKey, fileKey, envKey, `environment variable "` + envVar + `"`},
+		{"file used if environment variable is not set", "", "", fileKey + "\n", fileKey, fmt.Sprintf("key file %q", keyFile)},
+		{"key management service used as last resort", "", "", "", kmsKey, `key "my-key" from key management service`},
+	} {
+		flagValue := currCase.flagValue
+		t.Setenv(envVar, currCase.envValue)
+		_ = os.Remove(keyFile)
+		if currCase.fileValue != "" {
+			require.NoError(t, os.WriteFile(keyFile, []byte(currCase.fileValue), 0600), "Case %d: %s", i, currCase.name)
+		}
+
+		key, err := encryptedconfigvalue.ChainedKeySource(
+			encryptedconfigvalue.FlagKeySource("key", &flagValue),
+			encryptedconfigvalue.EnvKeySource(envVar),
+			encryptedconfigvalue.FileKeySource(keyFile),
+			encryptedconfigvalue.KMSKeySource(kms, "my-key"),
+		).Resolve()
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, key.ToSerializable(), "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.wantLabel, key.Describe().Label, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestChainedKeySourceNoKey(t *testing.T) {
+	const envVar = "ENCRYPTED_CONFIG_VALUE_TEST_KEY"
+	t.Setenv(envVar, "")
+	flagValue := ""
+	keyFile := filepath.Join(t.TempDir(), "missing.key")
+
+	_, err := encryptedconfigvalue.ChainedKeySource(
+		encryptedconfigvalue.FlagKeySource("key", &flagValue),
+		encryptedconfigvalue.EnvKeySource(envVar),
+		encryptedconfigvalue.FileKeySource(keyFile),
+		encryptedconfigvalue.KMSKeySource(testKMSClient{}, "my-key"),
+	).Resolve()
+	assert.True(t, errors.Is(err, encryptedconfigvalue.ErrKeyNotProvided))
+	assert.EqualError(t, err, `key not provided: none of the key sources provided a key: key not provided: flag "key" is not set; `+
+		`key not provided: environment variable "ENCRYPTED_CONFIG_VALUE_TEST_KEY" is not set; `+
+		fmt.Sprintf("key not provided: key file %q does not exist; ", keyFile)+
+		`failed to fetch key "my-key" from key management service: key not provided: no such key`)
+
+	_, err = encryptedconfigvalue.ChainedKeySource().Resolve()
+	assert.EqualError(t, err, "no key sources were provided")
+}
+
+func TestChainedKeySourceInvalidKey(t *testing.T) {
+	const envVar = "ENCRYPTED_CONFIG_VALUE_TEST_KEY"
+	t.Setenv(envVar, "AES:secret-material")
+	flagValue := ""
+
+	// a source that provides an invalid key or that fails is not skipped
+	for i, currCase := range []struct {
+		name    string
+		sources []encryptedconfigvalue.KeySource
+		wantErr string
+	}{
+		{
+			name: "invalid key",
+			sources: []encryptedconfigvalue.KeySource{
+				encryptedconfigvalue.FlagKeySource("key", &flagValue),
+				encryptedconfigvalue.EnvKeySource(envVar),
+			},
+			wantErr: `environment variable "ENCRYPTED_CONFIG_VALUE_TEST_KEY" does not contain a valid serialized key`,
+		},
+		{
+			name: "source error",
+			sources: []encryptedconfigvalue.KeySource{
+				encryptedconfigvalue.KeySourceFunc(func() (encryptedconfigvalue.KeyWithType, error) {
+					return encryptedconfigvalue.KeyWithType{}, errors.New("permission denied")
+				}),
+			},
+			wantErr: "permission denied",
+		},
+	} {
+		fallbackResolved := false
+		sources := append(currCase.sources, encryptedconfigvalue.KeySourceFunc(func() (encryptedconfigvalue.KeyWithType, error) {
+			fallbackResolved = true
+			return encryptedconfigvalue.NewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+		}))
+		_, err := encryptedconfigvalue.ChainedKeySource(sources...).Resolve()
+		assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+		assert.False(t, errors.Is(err, encryptedconfigvalue.ErrKeyNotProvided), "Case %d: %s", i, currCase.name)
+		assert.False(t, fallbackResolved, "Case %d: %s", i, currCase.name)
+	}
+}
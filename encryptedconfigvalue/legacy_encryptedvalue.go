@@ -17,11 +17,20 @@ type legacyEncryptedValue struct {
 // Decrypt decrypts this value using the provided key. Because legacy values do not track the type of the encrypted value
 // they contain, it will attempt to decrypt its content based on the key that is provided to the Decrypt function.
 func (ev *legacyEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	key, release, err := resolveKeyWithType(key)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
 	ciphertext := ev.encryptedBytes
 	switch key.Key.(type) {
 	default:
 		return "", fmt.Errorf("key type %T not supported", key.Key)
 	case *encryption.AESKey:
+		if len(ciphertext) < aesGCMLegacyNonceSizeBytes+aesGCMLegacyTagSizeBytes {
+			return "", fmt.Errorf("failed to decrypt value: ciphertext too short")
+		}
 		aesGCMEV := &aesGCMEncryptedValue{
 			encrypted: ciphertext[aesGCMLegacyNonceSizeBytes : len(ciphertext)-aesGCMLegacyTagSizeBytes],
 			nonce:     ciphertext[:aesGCMLegacyNonceSizeBytes],
@@ -38,9 +47,25 @@ func (ev *legacyEncryptedValue) Decrypt(key KeyWithType) (string, error) {
 	}
 }
 
+// DecryptValidUTF8 decrypts this legacy encrypted value and returns ErrInvalidUTF8 if the decrypted value is not valid
+// UTF-8.
+func (ev *legacyEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
 // ToSerializable returns the serializable representation for this legacy encrypted value, which is of the form:
 // "enc:<base64-encoded-ciphertext-bytes>". For AES values, the ciphertext bytes are "nonce+ciphertext+tag", while for
 // RSA values the ciphertext is the raw ciphertext.
 func (ev *legacyEncryptedValue) ToSerializable() SerializedEncryptedValue {
-	return newSerializedEncryptedValue(ev.encryptedBytes)
+	return toSerializable(ev)
+}
+
+// ToSerializableBytes returns the bytes of the serializable representation for this legacy encrypted value.
+func (ev *legacyEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return newSerializedEncryptedValueBytes(ev.encryptedBytes), nil
+}
+
+// KeyHint always returns false for legacy values because the legacy format does not support storing a key hint.
+func (ev *legacyEncryptedValue) KeyHint() (string, bool) {
+	return "", false
 }
@@ -0,0 +1,55 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitEncryptedValues parses the provided string, which consists of serialized encrypted values separated by sep (for
+// example, "enc:...,enc:..."), and returns the values in order. Whitespace around each element is ignored. Returns an
+// empty slice if the provided string is empty or consists only of whitespace. Returns an error if sep is empty or if
+// any element cannot be parsed.
+func SplitEncryptedValues(s string, sep string) ([]EncryptedValue, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("separator must not be empty")
+	}
+	if strings.TrimSpace(s) == "" {
+		return []EncryptedValue{}, nil
+	}
+	elems := strings.Split(s, sep)
+	evs := make([]EncryptedValue, len(elems))
+	for i, elem := range elems {
+		ev, err := NewEncryptedValue(strings.TrimSpace(elem))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse encrypted value at index %d: %v", i, err)
+		}
+		evs[i] = ev
+	}
+	return evs, nil
+}
+
+// JoinEncryptedValues returns the serialized forms of the provided values separated by sep. The returned string can
+// be parsed using SplitEncryptedValues with the same separator. Returns an error if sep is empty or contains a
+// character that may occur in a serialized value (a letter, digit, ':', '+', '/' or '=').
+func JoinEncryptedValues(evs []EncryptedValue, sep string) (string, error) {
+	if sep == "" {
+		return "", fmt.Errorf("separator must not be empty")
+	}
+	if strings.IndexFunc(sep, isSerializedValueRune) >= 0 {
+		return "", fmt.Errorf("separator %q contains a character that may occur in a serialized encrypted value", sep)
+	}
+	serialized := make([]string, len(evs))
+	for i, ev := range evs {
+		serialized[i] = string(ev.ToSerializable())
+	}
+	return strings.Join(serialized, sep), nil
+}
+
+// isSerializedValueRune returns true if the provided rune may occur in the serialized form of an encrypted value.
+func isSerializedValueRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(":+/=", r)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitJoinEncryptedValues(t *testing.T) {
+	evs := []encryptedconfigvalue.EncryptedValue{
+		encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal),
+		encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal),
+		encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal),
+	}
+	joined, err := encryptedconfigvalue.JoinEncryptedValues(evs, ",")
+	require.NoError(t, err)
+	assert.Equal(t, string(testAESEncryptedVal)+","+string(javaLegacyAESEncryptedVal)+","+string(testAESEncryptedVal), joined)
+
+	for i, currCase := range []struct {
+		name  string
+		input string
+		sep   string
+		want  int
+	}{
+		{
+			name:  "joined values",
+			input: joined,
+			sep:   ",",
+			want:  3,
+		},
+		{
+			name:  "surrounding whitespace",
+			input: " " + string(testAESEncryptedVal) + " ;\n\t" + string(testAESEncryptedVal) + "\n",
+			sep:   ";",
+			want:  2,
+		},
+		{
+			name:  "single value",
+			input: string(testAESEncryptedVal),
+			sep:   ",",
+			want:  1,
+		},
+		{
+			name:  "empty string",
+			input: "  ",
+			sep:   ",",
+			want:  0,
+		},
+	} {
+		split, err := encryptedconfigvalue.SplitEncryptedValues(currCase.input, currCase.sep)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		require.Len(t, split, currCase.want, "Case %d: %s", i, currCase.name)
+		for _, ev := range split {
+			assert.Contains(t, currCase.input, string(ev.ToSerializable()), "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestSplitJoinEncryptedValuesErrors(t *testing.T) {
+	_, err := encryptedconfigvalue.SplitEncryptedValues(string(testAESEncryptedVal)+",,"+string(testAESEncryptedVal), ",")
+	assert.EqualError(t, err, `failed to parse encrypted value at index 1: encrypted value must be of the form "enc:..." or "encc:..."`)
+	_, err = encryptedconfigvalue.SplitEncryptedValues(string(testAESEncryptedVal), "")
+	assert.EqualError(t, err, "separator must not be empty")
+
+	evs := []encryptedconfigvalue.EncryptedValue{encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)}
+	_, err = encryptedconfigvalue.JoinEncryptedValues(evs, "=")
+	assert.EqualError(t, err, `separator "=" contains a character that may occur in a serialized encrypted value`)
+	_, err = encryptedconfigvalue.JoinEncryptedValues(evs, "")
+	assert.EqualError(t, err, "separator must not be empty")
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// serializedValueRegexp matches the serialized form of values of the form "enc:..." in arbitrary text, including an
+// optional checksum suffix (as returned by ToSerializableChecksummed).
+var serializedValueRegexp = regexp.MustCompile(`enc:[A-Za-z0-9+/]+={0,2}(` + checksumSeparator + `[0-9a-f]{8})?`)
+
+// MigrateDirectory converts every value in the legacy format in the files in the provided directory (and its
+// subdirectories) to the new format in place. Values are found anywhere in the text of a file, so files of any format
+// (such as JSON, YAML or dotenv files) are supported, and all content of a file other than the migrated values is
+// preserved exactly. Every legacy value is decrypted using decryptKey and re-encrypted using encryptKey (using the
+// Encrypter for the algorithm type of encryptKey). Legacy values that have a checksum suffix are migrated to values
+// with a checksum suffix (as returned by ToSerializableChecksummed). Values that are part of a larger token (such as the value signed by
+// a value of the form "encs:...", whose signature would no longer match) are not considered.
+//
+// Returns the number of values that were migrated and the number of values that were skipped because they were
+// already in the new format. If dryRun is true, no files are modified and the returned counts are the values that
+// would have been migrated and skipped.
+//
+// Every file is migrated independently: if any legacy value in a file cannot be decrypted or re-encrypted, that file is
+// left unchanged, its values are not included in the returned counts, and the remaining files are still migrated.
+// Modified files are written to a temporary file in the same directory that is then renamed over the original, so a
+// file is never left partially written. The returned error describes every file that could not be migrated.
+func MigrateDirectory(dir string, decryptKey, encryptKey KeyWithType, dryRun bool) (migrated int, skipped int, err error) {
+	encrypter := encryptKey.Type.AlgorithmType().Encrypter()
+	var failures []string
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		fileMigrated, fileSkipped, err := migrateFile(path, decryptKey, encryptKey, encrypter, dryRun)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+			return nil
+		}
+		migrated += fileMigrated
+		skipped += fileSkipped
+		return nil
+	})
+	if walkErr != nil {
+		failures = append(failures, walkErr.Error())
+	}
+	if len(failures) > 0 {
+		return migrated, skipped, fmt.Errorf("failed to migrate %d file(s): %s", len(failures), strings.Join(failures, "; "))
+	}
+	return migrated, skipped, nil
+}
+
+// migrateFile migrates the legacy values in the file at the provided path as described by MigrateDirectory. The file
+// is only written if it contains at least one legacy value and dryRun is false.
+func migrateFile(path string, decryptKey, encryptKey KeyWithType, encrypter Encrypter, dryRun bool) (migrated int, skipped int, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var out []byte
+	last := 0
+	for _, loc := range serializedValueRegexp.FindAllIndex(content, -1) {
+		if loc[0] > 0 && isSerializedValueTokenByte(content[loc[0]-1]) {
+			continue
+		}
+		serialized := string(content[loc[0]:loc[1]])
+		ev, err := NewEncryptedValue(serialized)
+		if err == ErrChecksumMismatch {
+			return 0, 0, fmt.Errorf("invalid value at offset %d: %v", loc[0], err)
+		} else if err != nil {
+			// not an encrypted value
+			continue
+		}
+		if _, ok := ev.(*legacyEncryptedValue); !ok {
+			skipped++
+			continue
+		}
+		decrypted, err := ev.Decrypt(decryptKey)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to decrypt legacy value at offset %d: %v", loc[0], err)
+		}
+		reencrypted, err := encrypter.Encrypt(decrypted, encryptKey)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to encrypt value at offset %d: %v", loc[0], err)
+		}
+		out = append(out, content[last:loc[0]]...)
+		if strings.Contains(serialized, checksumSeparator) {
+			out = append(out, ToSerializableChecksummed(reencrypted)...)
+		} else {
+			out = append(out, reencrypted.ToSerializable()...)
+		}
+		last = loc[1]
+		migrated++
+	}
+	if migrated == 0 || dryRun {
+		return migrated, skipped, nil
+	}
+	out = append(out, content[last:]...)
+	if err := writeFileAtomic(path, out); err != nil {
+		return 0, 0, err
+	}
+	return migrated, skipped, nil
+}
+
+// isSerializedValueTokenByte returns true if a serialized value that follows the provided byte is part of a larger
+// token: this is the case if the byte is a colon (as in "encs:<signature>:enc:...") or a base64 character other than
+// padding. Padding is excluded because "=" commonly precedes values in assignments (as in "KEY=enc:...").
+func isSerializedValueTokenByte(b byte) bool {
+	return b == ':' || b == '+' || b == '/' ||
+		('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// writeFileAtomic replaces the content of the file at the provided path with the provided content by writing it to a
+// temporary file in the same directory, syncing it to disk and renaming the temporary file over the original, so the
+// original file is never left partially written. The permissions of the original file are preserved.
+func writeFileAtomic(path string, content []byte) (rErr error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err := tmp.Write(content); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDirectory(t *testing.T) {
+	decryptKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaAESKey)
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.json": fmt.Sprintf(`{
+  "legacy": "%s",
+  "current": "%s"
+}
+`, javaLegacyAESEncryptedVal, testAESEncryptedVal),
+		filepath.Join("nested", "app.yml"): fmt.Sprintf("secrets:\n  - %s # legacy\n  - %s\n", javaLegacyAESEncryptedVal, javaLegacyAESEncryptedVal),
+		"plain.txt":                        "no encrypted values",
+		// the legacy RSA value cannot be decrypted using the AES key, so this file is not migrated
+		"broken.env": fmt.Sprintf("A=%s\nB=%s\n", javaLegacyAESEncryptedVal, javaLegacyRSAEncryptedVal),
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	// dry run reports the counts without modifying any files
+	migrated, skipped, err := encryptedconfigvalue.MigrateDirectory(dir, decryptKey, newKP.EncryptionKey, true)
+	require.Error(t, err)
+	assert.Regexp(t, `^failed to migrate 1 file\(s\): .*broken\.env: failed to decrypt legacy value at offset \d+: `, err.Error())
+	assert.Equal(t, 3, migrated)
+	assert.Equal(t, 1, skipped)
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(got), name)
+	}
+
+	migrated, skipped, err = encryptedconfigvalue.MigrateDirectory(dir, decryptKey, newKP.EncryptionKey, false)
+	require.Error(t, err)
+	assert.Equal(t, 3, migrated)
+	assert.Equal(t, 1, skipped)
+
+	// formatting is preserved and migrated values decrypt using the new key
+	valueRegexp := regexp.MustCompile(`enc:[A-Za-z0-9+/=]+`)
+	got, err := os.ReadFile(filepath.Join(dir, "nested", "app.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "secrets:\n  - X # legacy\n  - X\n", valueRegexp.ReplaceAllString(string(got), "X"))
+	for _, serialized := range valueRegexp.FindAllString(string(got), -1) {
+		decrypted, err := encryptedconfigvalue.MustNewEncryptedValue(serialized).Decrypt(newKP.DecryptionKey)
+		require.NoError(t, err)
+		assert.Equal(t, javaPlaintext, decrypted)
+	}
+	got, err = os.ReadFile(filepath.Join(dir, "app.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(got), string(javaLegacyAESEncryptedVal))
+	assert.Contains(t, string(got), string(testAESEncryptedVal))
+	info, err := os.Stat(filepath.Join(dir, "app.json"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the file that could not be migrated is unchanged
+	got, err = os.ReadFile(filepath.Join(dir, "broken.env"))
+	require.NoError(t, err)
+	assert.Equal(t, files["broken.env"], string(got))
+
+	// migrating again skips the values that were migrated
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+	require.NoError(t, os.Remove(filepath.Join(dir, "broken.env")))
+	migrated, skipped, err = encryptedconfigvalue.MigrateDirectory(dir, decryptKey, newKP.EncryptionKey, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+	assert.Equal(t, 4, skipped)
+}
+
+func TestMigrateDirectoryChecksummedValue(t *testing.T) {
+	decryptKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaAESKey)
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	checksummed := encryptedconfigvalue.ToSerializableChecksummed(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.json")
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{"a": "%s"}`, checksummed)), 0600))
+
+	migrated, skipped, err := encryptedconfigvalue.MigrateDirectory(dir, decryptKey, newKP.EncryptionKey, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+	assert.Equal(t, 0, skipped)
+
+	var values map[string]string
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &values))
+	assert.Regexp(t, `^enc:[A-Za-z0-9+/=]+#[0-9a-f]{8}$`, values["a"])
+	ev, err := encryptedconfigvalue.NewEncryptedValue(values["a"])
+	require.NoError(t, err)
+	decrypted, err := ev.Decrypt(newKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, javaPlaintext, decrypted)
+
+	// a value with a corrupted checksum is reported rather than ignored
+	corrupted := string(checksummed[:len(checksummed)-1]) + "0"
+	if corrupted == string(checksummed) {
+		corrupted = string(checksummed[:len(checksummed)-1]) + "1"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{"a": "%s"}`, corrupted)), 0600))
+	_, _, err = encryptedconfigvalue.MigrateDirectory(dir, decryptKey, newKP.EncryptionKey, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch, value may be corrupted")
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWeakLegacyAlgorithm is returned by ParseEncryptedValue when it is configured using RejectWeakLegacyAlgorithms and
+// the provided value is in the legacy format. Legacy values are encrypted using AES-GCM with a 256-bit nonce or using
+// RSA-OAEP with SHA-1 as the MGF1 hash algorithm, and do not record the algorithm or parameters used to encrypt them.
+var ErrWeakLegacyAlgorithm = errors.New("encrypted value is in the legacy format")
+
+// ParseOption configures ParseEncryptedValue.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	rejectWeakLegacy bool
+}
+
+// RejectWeakLegacyAlgorithms returns a ParseOption that configures ParseEncryptedValue to return an error that wraps
+// ErrWeakLegacyAlgorithm for values in the legacy format.
+func RejectWeakLegacyAlgorithms() ParseOption {
+	return func(o *parseOptions) {
+		o.rejectWeakLegacy = true
+	}
+}
+
+// ParseEncryptedValue creates a new encrypted value from its string representation in the same manner as
+// NewEncryptedValue, configured using the provided options. If no options are provided, it is equivalent to
+// NewEncryptedValue.
+func ParseEncryptedValue(evStr string, opts ...ParseOption) (EncryptedValue, error) {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ev, err := NewEncryptedValue(evStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ev.(*legacyEncryptedValue); ok && o.rejectWeakLegacy {
+		return nil, fmt.Errorf("%w: re-encrypt the value using the current format, or use UnsafeDecryptWeakLegacy to decrypt it", ErrWeakLegacyAlgorithm)
+	}
+	return ev, nil
+}
+
+// UnsafeDecryptWeakLegacy parses the provided serialized encrypted value, which may be in the legacy format, and
+// decrypts it using the provided key. It is intended for decrypting values that are rejected by ParseEncryptedValue
+// with RejectWeakLegacyAlgorithms (for example, in order to re-encrypt them) and makes such uses explicit.
+func UnsafeDecryptWeakLegacy(evStr string, key KeyWithType) (string, error) {
+	ev, err := NewEncryptedValue(evStr)
+	if err != nil {
+		return "", err
+	}
+	return ev.Decrypt(key)
+}
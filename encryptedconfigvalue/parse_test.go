@@ -0,0 +1,58 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEncryptedValueRejectWeakLegacyAlgorithms(t *testing.T) {
+	for i, currCase := range []struct {
+		name   string
+		input  encryptedconfigvalue.SerializedEncryptedValue
+		legacy bool
+	}{
+		{
+			name:  "AES value",
+			input: testAESEncryptedVal,
+		},
+		{
+			name:  "RSA value",
+			input: testRSAEncryptedVal,
+		},
+		{
+			name:   "legacy AES value",
+			input:  javaLegacyAESEncryptedVal,
+			legacy: true,
+		},
+		{
+			name:   "legacy RSA value",
+			input:  javaLegacyRSAEncryptedVal,
+			legacy: true,
+		},
+	} {
+		// legacy values are accepted by default
+		_, err := encryptedconfigvalue.ParseEncryptedValue(string(currCase.input))
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		_, err = encryptedconfigvalue.ParseEncryptedValue(string(currCase.input), encryptedconfigvalue.RejectWeakLegacyAlgorithms())
+		if currCase.legacy {
+			assert.True(t, errors.Is(err, encryptedconfigvalue.ErrWeakLegacyAlgorithm), "Case %d: %s", i, currCase.name)
+		} else {
+			assert.NoError(t, err, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestUnsafeDecryptWeakLegacy(t *testing.T) {
+	decrypted, err := encryptedconfigvalue.UnsafeDecryptWeakLegacy(string(javaLegacyAESEncryptedVal), encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaAESKey))
+	require.NoError(t, err)
+	assert.Equal(t, javaPlaintext, decrypted)
+}
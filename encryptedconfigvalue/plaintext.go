@@ -0,0 +1,37 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTF8 is returned by the DecryptValidUTF8 function of an EncryptedValue when the decrypted value is not valid
+// UTF-8.
+var ErrInvalidUTF8 = errors.New("decrypted value is not valid UTF-8")
+
+// DecryptBytes decrypts the provided value using the provided key and returns the decrypted bytes. It should be used
+// for values whose plaintext is binary data, which is not necessarily valid UTF-8.
+func DecryptBytes(ev EncryptedValue, key KeyWithType) ([]byte, error) {
+	decrypted, err := ev.Decrypt(key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decrypted), nil
+}
+
+// decryptValidUTF8 decrypts the provided value using its Decrypt function and returns ErrInvalidUTF8 if the decrypted
+// value is not valid UTF-8. It is the implementation of the DecryptValidUTF8 function of every EncryptedValue.
+func decryptValidUTF8(ev EncryptedValue, key KeyWithType) (string, error) {
+	decrypted, err := ev.Decrypt(key)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.ValidString(decrypted) {
+		return "", ErrInvalidUTF8
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecryptValidUTF8(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name      string
+		plaintext string
+		valid     bool
+	}{
+		{
+			name:      "ASCII",
+			plaintext: "plaintext",
+			valid:     true,
+		},
+		{
+			name:      "multi-byte characters",
+			plaintext: "pässwörd ✓",
+			valid:     true,
+		},
+		{
+			name:      "empty",
+			plaintext: "",
+			valid:     true,
+		},
+		{
+			name:      "binary",
+			plaintext: string([]byte{0xff, 0xfe, 0x00, 0x01}),
+		},
+	} {
+		ev, err := encryptedconfigvalue.AES.Encrypter().Encrypt(currCase.plaintext, kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		decryptedBytes, err := encryptedconfigvalue.DecryptBytes(ev, kp.DecryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, []byte(currCase.plaintext), decryptedBytes, "Case %d: %s", i, currCase.name)
+
+		decrypted, err := ev.DecryptValidUTF8(kp.DecryptionKey)
+		if currCase.valid {
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, currCase.plaintext, decrypted, "Case %d: %s", i, currCase.name)
+		} else {
+			assert.Equal(t, encryptedconfigvalue.ErrInvalidUTF8, err, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PolicyEnforcer is invoked by a Decrypter configured using WithPolicyEnforcer for every value that has a policy. The
+// policy is provided after the value has been decrypted (and the policy authenticated), but before the plaintext is
+// returned. Returning an error aborts decryption. Numbers in the policy are provided as json.Number values.
+type PolicyEnforcer func(policy map[string]interface{}) error
+
+// policyEncrypter is implemented by Encrypter implementations that can store an authenticated policy in the values
+// they create.
+type policyEncrypter interface {
+	encryptWithPolicy(input string, key KeyWithType, policy []byte) (EncryptedValue, error)
+}
+
+// policyValue is implemented by EncryptedValue implementations that can store an authenticated policy. policy returns
+// the canonical JSON representation of the policy of the value, or nil if the value does not have a policy.
+type policyValue interface {
+	policy() []byte
+}
+
+type policyEncrypterWrapper struct {
+	encrypter Encrypter
+	policy    map[string]interface{}
+}
+
+// WithPolicy returns an Encrypter that encrypts values using the provided encrypter and stores the provided policy
+// (for example, {"environments": ["prod"]}) in every EncryptedValue that it returns. The policy is stored in the clear,
+// but it is authenticated as part of the encryption, so it cannot be modified or removed without causing decryption to
+// fail. Values with a policy can only be decrypted using a Decrypter configured using WithPolicyEnforcer, which
+// receives the policy before the plaintext is returned. Encrypting with the returned Encrypter fails if the provided
+// policy cannot be represented as JSON or if the provided encrypter does not support policies (only the encrypters
+// returned by NewAESGCMEncrypter and NewRSAOAEPEncrypter support policies).
+func WithPolicy(encrypter Encrypter, policy map[string]interface{}) Encrypter {
+	return &policyEncrypterWrapper{
+		encrypter: encrypter,
+		policy:    policy,
+	}
+}
+
+func (e *policyEncrypterWrapper) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	policyEnc, ok := e.encrypter.(policyEncrypter)
+	if !ok {
+		return nil, fmt.Errorf("encrypter of type %T does not support policies", e.encrypter)
+	}
+	policy, err := json.Marshal(e.policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy: %v", err)
+	}
+	canonical, err := canonicalPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return policyEnc.encryptWithPolicy(input, key, canonical)
+}
+
+// canonicalPolicy returns the canonical JSON representation of the provided JSON policy, which is the policy with
+// object keys sorted and whitespace removed. The canonical representation is the data that is authenticated, so the
+// policy of a serialized value can be reformatted without causing decryption to fail. Returns an error if the provided
+// JSON is not an object.
+func canonicalPolicy(policy []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(policy))
+	dec.UseNumber()
+	var policyMap map[string]interface{}
+	if err := dec.Decode(&policyMap); err != nil || policyMap == nil {
+		return nil, fmt.Errorf("policy must be a JSON object")
+	}
+	return json.Marshal(policyMap)
+}
+
+// decodePolicy returns the map representation of the provided canonical JSON policy.
+func decodePolicy(policy []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(policy))
+	dec.UseNumber()
+	var policyMap map[string]interface{}
+	if err := dec.Decode(&policyMap); err != nil {
+		return nil, err
+	}
+	return policyMap, nil
+}
+
+const policyAdditionalDataPrefix = "policy:"
+
+// additionalData returns the data that is authenticated when encrypting or decrypting a value with the provided
+// context and canonical policy. The additional data of values without a policy is the context, so that such values
+// remain compatible with values created before policies were supported. Otherwise, it is "policy:", followed by the
+// length of the policy as an 8-byte big-endian integer, the policy and the context.
+func additionalData(context, policy []byte) []byte {
+	if policy == nil {
+		return context
+	}
+	out := make([]byte, 0, len(policyAdditionalDataPrefix)+8+len(policy)+len(context))
+	out = append(out, policyAdditionalDataPrefix...)
+	out = binary.BigEndian.AppendUint64(out, uint64(len(policy)))
+	out = append(out, policy...)
+	return append(out, context...)
+}
+
+// errPolicyNotEnforced is returned when a value with a policy is decrypted without a PolicyEnforcer.
+var errPolicyNotEnforced = errors.New("encrypted value has a policy and must be decrypted using a Decrypter configured using WithPolicyEnforcer")
@@ -0,0 +1,122 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPolicy(t *testing.T) {
+	policy := map[string]interface{}{
+		"environments": []string{"prod"},
+		"requires-mfa": true,
+		"max-age":      30,
+	}
+	errRejected := errors.New("rejected")
+
+	for i, currAlg := range []encryptedconfigvalue.AlgorithmType{
+		encryptedconfigvalue.AES,
+		encryptedconfigvalue.RSA,
+	} {
+		kp, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		ev, err := encryptedconfigvalue.WithPolicy(currAlg.Encrypter(), policy).Encrypt("secret", kp.EncryptionKey)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+
+		// values with a policy cannot be decrypted without an enforcer
+		_, err = ev.Decrypt(kp.DecryptionKey)
+		assert.EqualError(t, err, "encrypted value has a policy and must be decrypted using a Decrypter configured using WithPolicyEnforcer", "Case %d: %s", i, currAlg)
+		_, err = encryptedconfigvalue.NewDecrypter(kp.DecryptionKey).Decrypt(ev)
+		assert.Error(t, err, "Case %d: %s", i, currAlg)
+
+		var enforcedPolicy map[string]interface{}
+		decrypted, err := encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, encryptedconfigvalue.WithPolicyEnforcer(func(policy map[string]interface{}) error {
+			enforcedPolicy = policy
+			return nil
+		})).Decrypt(ev)
+		require.NoError(t, err, "Case %d: %s", i, currAlg)
+		assert.Equal(t, "secret", decrypted, "Case %d: %s", i, currAlg)
+		assert.Equal(t, map[string]interface{}{
+			"environments": []interface{}{"prod"},
+			"requires-mfa": true,
+			"max-age":      json.Number("30"),
+		}, enforcedPolicy, "Case %d: %s", i, currAlg)
+
+		_, err = encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, encryptedconfigvalue.WithPolicyEnforcer(func(policy map[string]interface{}) error {
+			return errRejected
+		})).Decrypt(ev)
+		assert.True(t, errors.Is(err, errRejected), "Case %d: %s", i, currAlg)
+	}
+}
+
+func TestWithPolicyTampered(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	ev, err := encryptedconfigvalue.WithPolicy(encryptedconfigvalue.AES.Encrypter(), map[string]interface{}{
+		"environments": []string{"prod"},
+	}).Encrypt("secret", kp.EncryptionKey)
+	require.NoError(t, err)
+	jsonBytes, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(ev.ToSerializable()), "enc:"))
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonBytes, &fields))
+	acceptAll := encryptedconfigvalue.WithPolicyEnforcer(func(policy map[string]interface{}) error {
+		return nil
+	})
+
+	for i, currCase := range []struct {
+		name    string
+		policy  interface{}
+		wantErr bool
+	}{
+		{
+			name:   "reformatted policy",
+			policy: map[string]interface{}{"environments": []string{"prod"}},
+		},
+		{
+			name:    "modified policy",
+			policy:  map[string]interface{}{"environments": []string{"prod", "dev"}},
+			wantErr: true,
+		},
+		{
+			name:    "removed policy",
+			wantErr: true,
+		},
+	} {
+		if currCase.policy == nil {
+			delete(fields, "policy")
+		} else {
+			fields["policy"] = currCase.policy
+		}
+		modified, err := json.MarshalIndent(fields, "", "  ")
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		modifiedEV := encryptedconfigvalue.MustNewEncryptedValue("enc:" + base64.StdEncoding.EncodeToString(modified))
+
+		decrypted, err := encryptedconfigvalue.NewDecrypter(kp.DecryptionKey, acceptAll).Decrypt(modifiedEV)
+		if currCase.wantErr {
+			assert.Error(t, err, "Case %d: %s", i, currCase.name)
+		} else {
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, "secret", decrypted, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestWithPolicyUnsupported(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.WithPolicy(encryptedconfigvalue.LegacyAESGCMEncrypter(), map[string]interface{}{}).Encrypt("secret", kp.EncryptionKey)
+	assert.EqualError(t, err, "encrypter of type *encryptedconfigvalue.legacyAESGCMEncrypter does not support policies")
+}
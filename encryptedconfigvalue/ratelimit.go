@@ -0,0 +1,91 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by a Decrypter configured using WithAsymmetricRateLimit when decrypting a value would
+// exceed its rate limit.
+var ErrRateLimited = errors.New("rate limit for asymmetric decryption operations exceeded")
+
+type rateLimit struct {
+	opsPerSecond float64
+	burst        int
+}
+
+// WithAsymmetricRateLimit returns an Option that limits the rate at which a Decrypter performs asymmetric (RSA)
+// decryption operations, which are much more expensive than symmetric ones. The limit is a token bucket that holds up
+// to burst tokens and is refilled at opsPerSecond tokens per second: every value that requires an RSA private key
+// operation takes a token, and the Decrypter returns ErrRateLimited without decrypting the value if no token is
+// available. Values that are decrypted using an AES key are never limited. A burst smaller than 1 is treated as 1, and
+// a non-positive rate means that the bucket is never refilled. Every Decrypter has its own bucket, which is safe for
+// concurrent use.
+func WithAsymmetricRateLimit(opsPerSecond float64, burst int) Option {
+	return func(o *decrypterOptions) {
+		o.asymmetricRateLimit = &rateLimit{
+			opsPerSecond: opsPerSecond,
+			burst:        burst,
+		}
+	}
+}
+
+// requiresAsymmetricOperation returns true if decrypting the provided value using the provided key performs an RSA
+// private key operation.
+func requiresAsymmetricOperation(ev EncryptedValue, key KeyWithType) bool {
+	if key.Type != RSAPrivKey {
+		return false
+	}
+	if algValue, ok := ev.(algorithmValue); ok {
+		return algValue.algorithm() != AES
+	}
+	return true
+}
+
+// tokenBucket is a token bucket rate limiter that is safe for concurrent use.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newTokenBucket(limit rateLimit, now func() time.Time) *tokenBucket {
+	capacity := float64(limit.burst)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{
+		rate:     limit.opsPerSecond,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     now(),
+		now:      now,
+	}
+}
+
+// take takes a token from the bucket and returns true, or returns false if the bucket is empty.
+func (b *tokenBucket) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if b.rate > 0 {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	bucket := newTokenBucket(rateLimit{opsPerSecond: 2, burst: 3}, func() time.Time {
+		return now
+	})
+
+	// full bucket allows a burst
+	for i := 0; i < 3; i++ {
+		assert.True(t, bucket.take(), "take %d", i)
+	}
+	assert.False(t, bucket.take())
+
+	// refilled at the configured rate
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.take())
+	assert.False(t, bucket.take())
+
+	// never refilled beyond its capacity
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, bucket.take(), "take %d", i)
+	}
+	assert.False(t, bucket.take())
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"fmt"
+	"io"
+)
+
+// ValueMetadata is the metadata of an encrypted value in a JSON document that can be determined without decrypting
+// the value.
+type ValueMetadata struct {
+	// Path is the JSON Pointer (RFC 6901) for the value in the document, for example "/db/password".
+	Path string
+	// Algorithm is the algorithm used to encrypt the value. It is empty if Legacy is true.
+	Algorithm AlgorithmType
+	// Legacy is true if the value is in the legacy format, which does not record its algorithm.
+	Legacy bool
+	// KeyHint is the key hint stored with the value, or an empty string if the value does not have a key hint.
+	KeyHint string
+}
+
+// newValueMetadata returns the ValueMetadata for the provided value that occurs at the provided path.
+func newValueMetadata(path string, ev EncryptedValue) ValueMetadata {
+	meta := ValueMetadata{
+		Path: path,
+	}
+	if algValue, ok := ev.(algorithmValue); ok {
+		meta.Algorithm = algValue.algorithm()
+	} else {
+		meta.Legacy = true
+	}
+	meta.KeyHint, _ = ev.KeyHint()
+	return meta
+}
+
+// RotateMatching returns a copy of the provided JSON document in which every encrypted value (see DecryptAllInJSON) for
+// which match returns true is decrypted using oldKey and re-encrypted using newKey (using the Encrypter for the
+// algorithm type of newKey). The key hint of a rotated value is preserved. Values for which match returns false are
+// left unchanged, as is all other content of the document. Returns an error that identifies the JSON path of the first
+// matching value that cannot be parsed, decrypted or re-encrypted.
+func RotateMatching(data []byte, match func(meta ValueMetadata) bool, oldKey, newKey KeyWithType) ([]byte, error) {
+	nodes, err := encryptedJSONStringValues(data)
+	if err != nil {
+		return nil, err
+	}
+	encrypter := newKey.Type.AlgorithmType().Encrypter()
+
+	var rotatedNodes []jsonStringValue
+	var rotated []string
+	for _, node := range nodes {
+		ev, err := NewEncryptedValue(node.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse encrypted value at %q: %v", node.path, err)
+		}
+		if !match(newValueMetadata(node.path, ev)) {
+			continue
+		}
+		decrypted, err := ev.Decrypt(oldKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt value at %q: %v", node.path, err)
+		}
+		reencrypted, err := reencryptValue(ev, decrypted, encrypter, newKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt value at %q: %v", node.path, err)
+		}
+		rotatedNodes = append(rotatedNodes, node)
+		rotated = append(rotated, string(reencrypted.ToSerializable()))
+	}
+	return replaceJSONStringValues(data, rotatedNodes, rotated), nil
+}
+
+// reencryptValue encrypts the decrypted plaintext of the provided value using encrypter and key. The key hint of the
+// provided value, if any, is carried over to the re-encrypted value if its format can store a key hint.
+func reencryptValue(ev EncryptedValue, decrypted string, encrypter Encrypter, key KeyWithType) (EncryptedValue, error) {
+	reencrypted, err := encrypter.Encrypt(decrypted, key)
+	if err != nil {
+		return nil, err
+	}
+	if hint, ok := ev.KeyHint(); ok {
+		if setter, ok := reencrypted.(keyHintSetter); ok {
+			setter.setKeyHint(hint)
+		}
+	}
+	return reencrypted, nil
+}
+
+// RotateStream behaves like RotateMatching with a predicate that matches every value, but reads the JSON document from
+// the provided reader and writes the result to the provided writer incrementally as the document is read, in the same
+// manner as DecryptAllInJSONStream. The amount of memory used is bounded by the size of the largest single token in
+// the document, and all content of the document other than the rotated values (including whitespace and the order of
+// object keys) is preserved exactly. Only JSON documents are supported. Returns an error that identifies the JSON
+// path of the first value that cannot be parsed, decrypted or re-encrypted. If an error occurs, the output that
+// precedes the failing value may already have been written to the writer.
+func RotateStream(r io.Reader, w io.Writer, oldKey, newKey KeyWithType) error {
+	encrypter := newKey.Type.AlgorithmType().Encrypter()
+	return transformJSONStream(r, w, func(node jsonStringValue) (string, bool, error) {
+		if !isEncryptedValueString(node.value) {
+			return "", false, nil
+		}
+		ev, err := NewEncryptedValue(node.value)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to parse encrypted value at %q: %v", node.path, err)
+		}
+		decrypted, err := ev.Decrypt(oldKey)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to decrypt value at %q: %v", node.path, err)
+		}
+		reencrypted, err := reencryptValue(ev, decrypted, encrypter, newKey)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to encrypt value at %q: %v", node.path, err)
+		}
+		return string(reencrypted.ToSerializable()), true, nil
+	})
+}
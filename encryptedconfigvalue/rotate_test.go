@@ -0,0 +1,132 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateMatching(t *testing.T) {
+	oldKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	hinted, err := encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.AES.Encrypter(), "old-kms").Encrypt("hinted", oldKey)
+	require.NoError(t, err)
+
+	input := fmt.Sprintf(`{"a": "%s", "b": "%s", "c": "%s", "d": "plain"}`, testAESEncryptedVal, hinted.ToSerializable(), testRSAEncryptedVal)
+
+	var seen []encryptedconfigvalue.ValueMetadata
+	got, err := encryptedconfigvalue.RotateMatching([]byte(input), func(meta encryptedconfigvalue.ValueMetadata) bool {
+		seen = append(seen, meta)
+		return meta.KeyHint == "old-kms" || meta.Path == "/a"
+	}, oldKey, newKP.EncryptionKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, []encryptedconfigvalue.ValueMetadata{
+		{Path: "/a", Algorithm: encryptedconfigvalue.AES},
+		{Path: "/b", Algorithm: encryptedconfigvalue.AES, KeyHint: "old-kms"},
+		{Path: "/c", Algorithm: encryptedconfigvalue.RSA},
+	}, seen)
+
+	var values map[string]string
+	require.NoError(t, json.Unmarshal(got, &values))
+	assert.Equal(t, string(testRSAEncryptedVal), values["c"])
+	assert.Equal(t, "plain", values["d"])
+	for k, want := range map[string]string{"a": "plaintext", "b": "hinted"} {
+		assert.True(t, strings.HasPrefix(values[k], "enc:"), k)
+		decrypted, err := encryptedconfigvalue.MustNewEncryptedValue(values[k]).Decrypt(newKP.DecryptionKey)
+		require.NoError(t, err, k)
+		assert.Equal(t, want, decrypted, k)
+	}
+
+	// the key hint of a rotated value is preserved
+	hint, ok := encryptedconfigvalue.MustNewEncryptedValue(values["b"]).KeyHint()
+	assert.True(t, ok)
+	assert.Equal(t, "old-kms", hint)
+	_, ok = encryptedconfigvalue.MustNewEncryptedValue(values["a"]).KeyHint()
+	assert.False(t, ok)
+}
+
+func TestRotateMatchingLegacyMetadata(t *testing.T) {
+	oldKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(javaAESKey)
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	input := fmt.Sprintf(`["%s"]`, javaLegacyAESEncryptedVal)
+	got, err := encryptedconfigvalue.RotateMatching([]byte(input), func(meta encryptedconfigvalue.ValueMetadata) bool {
+		assert.Equal(t, encryptedconfigvalue.ValueMetadata{Path: "/0", Legacy: true}, meta)
+		return meta.Legacy
+	}, oldKey, newKP.EncryptionKey)
+	require.NoError(t, err)
+
+	var values []string
+	require.NoError(t, json.Unmarshal(got, &values))
+	decrypted, err := encryptedconfigvalue.MustNewEncryptedValue(values[0]).Decrypt(newKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, javaPlaintext, decrypted)
+}
+
+func TestRotateMatchingWrongKey(t *testing.T) {
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = encryptedconfigvalue.RotateMatching([]byte(fmt.Sprintf(`{"a": "%s"}`, testAESEncryptedVal)), func(encryptedconfigvalue.ValueMetadata) bool {
+		return true
+	}, newKP.DecryptionKey, newKP.EncryptionKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decrypt value at "/a"`)
+}
+
+func TestRotateStream(t *testing.T) {
+	oldKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	newKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	input := fmt.Sprintf(`{
+  "b": 1.50,
+  "a": {"password": "%s", "enc:key": "x"},
+  "list": [ "plain", "%s" ]
+}
+`, testAESEncryptedVal, testAESEncryptedVal)
+
+	// read one byte at a time to verify that tokens that span reads are handled
+	var out bytes.Buffer
+	require.NoError(t, encryptedconfigvalue.RotateStream(iotest.OneByteReader(strings.NewReader(input)), &out, oldKey, newKP.EncryptionKey))
+	assert.NotContains(t, out.String(), string(testAESEncryptedVal))
+
+	decrypted, err := encryptedconfigvalue.DecryptAllInJSON(out.Bytes(), newKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(input, string(testAESEncryptedVal), "plaintext", -1), string(decrypted))
+
+	// the key hint of a rotated value is preserved
+	hinted, err := encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.AES.Encrypter(), "old-kms").Encrypt("hinted", oldKey)
+	require.NoError(t, err)
+	out.Reset()
+	require.NoError(t, encryptedconfigvalue.RotateStream(strings.NewReader(fmt.Sprintf(`["%s"]`, hinted.ToSerializable())), &out, oldKey, newKP.EncryptionKey))
+	var values []string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &values))
+	require.Len(t, values, 1)
+	rotated := encryptedconfigvalue.MustNewEncryptedValue(values[0])
+	hint, ok := rotated.KeyHint()
+	assert.True(t, ok)
+	assert.Equal(t, "old-kms", hint)
+	decryptedHinted, err := rotated.Decrypt(newKP.DecryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, "hinted", decryptedHinted)
+
+	err = encryptedconfigvalue.RotateStream(strings.NewReader(fmt.Sprintf(`{"a": "%s", "rsa": ["%s"]}`, testAESEncryptedVal, testRSAEncryptedVal)), io.Discard, oldKey, newKP.EncryptionKey)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to decrypt value at "/rsa/0"`)
+}
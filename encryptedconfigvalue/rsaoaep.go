@@ -53,15 +53,28 @@ func NewRSAOAEPEncrypter() Encrypter {
 }
 
 func (r *rsaOAEPEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	return r.encryptWithContext(input, key, nil)
+}
+
+func (r *rsaOAEPEncrypter) encryptWithContext(input string, key KeyWithType, context []byte) (EncryptedValue, error) {
+	return r.encrypt(input, key, context, nil)
+}
+
+func (r *rsaOAEPEncrypter) encryptWithPolicy(input string, key KeyWithType, policy []byte) (EncryptedValue, error) {
+	return r.encrypt(input, key, nil, policy)
+}
+
+func (r *rsaOAEPEncrypter) encrypt(input string, key KeyWithType, context, policy []byte) (EncryptedValue, error) {
 	rsaOAEPCipher := (*encryption.RSAOAEPCipher)(r)
-	encrypted, err := rsaOAEPCipher.Encrypt([]byte(input), key.Key)
+	encrypted, err := rsaOAEPCipher.EncryptWithLabel([]byte(input), additionalData(context, policy), key.Key)
 	if err != nil {
 		return nil, err
 	}
 	return &rsaOAEPEncryptedValue{
-		encrypted:   encrypted,
-		oaepHashAlg: rsaOAEPCipher.OAEPHashAlg(),
-		mdf1HashAlg: rsaOAEPCipher.MDF1HashAlg(),
+		encrypted:    encrypted,
+		oaepHashAlg:  rsaOAEPCipher.OAEPHashAlg(),
+		mdf1HashAlg:  rsaOAEPCipher.MDF1HashAlg(),
+		policyConfig: policy,
 	}, nil
 }
 
@@ -71,14 +84,21 @@ type rsaOAEPEncryptedValue struct {
 	encrypted   []byte
 	oaepHashAlg encryption.HashAlgorithm
 	mdf1HashAlg encryption.HashAlgorithm
+	keyHint     string
+	// policyConfig is the canonical JSON representation of the authenticated policy of the value, or nil if the value
+	// does not have a policy.
+	policyConfig []byte
+	parsedJSON
 }
 
 type rsaOAEPEncryptedValueJSON struct {
-	Type        string `json:"type"`
-	Mode        string `json:"mode"`
-	Ciphertext  string `json:"ciphertext"`
-	OAEPHashAlg string `json:"oaep-alg"`
-	MDF1HashAlg string `json:"mdf1-alg"`
+	Type        string          `json:"type"`
+	Mode        string          `json:"mode"`
+	Ciphertext  string          `json:"ciphertext"`
+	OAEPHashAlg string          `json:"oaep-alg"`
+	MDF1HashAlg string          `json:"mdf1-alg"`
+	KeyHint     string          `json:"key_hint,omitempty"`
+	Policy      json.RawMessage `json:"policy,omitempty"`
 }
 
 func (ev rsaOAEPEncryptedValue) MarshalJSON() ([]byte, error) {
@@ -88,6 +108,8 @@ func (ev rsaOAEPEncryptedValue) MarshalJSON() ([]byte, error) {
 		Ciphertext:  base64.StdEncoding.EncodeToString(ev.encrypted),
 		OAEPHashAlg: string(ev.oaepHashAlg),
 		MDF1HashAlg: string(ev.mdf1HashAlg),
+		KeyHint:     ev.keyHint,
+		Policy:      ev.policyConfig,
 	})
 }
 
@@ -105,28 +127,80 @@ func (ev *rsaOAEPEncryptedValue) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	oaepHashAlg := encryption.HashAlgorithm(evJSON.OAEPHashAlg)
-	if oaepHashAlg.Hash() == nil {
+	if !isSupportedHashAlgorithm(oaepHashAlg) {
 		return fmt.Errorf("unrecognized hash algorithm %q specified as OAEP hash algorithm", evJSON.OAEPHashAlg)
 	}
 	mdf1HashAlg := encryption.HashAlgorithm(evJSON.MDF1HashAlg)
-	if mdf1HashAlg.Hash() == nil {
+	if !isSupportedHashAlgorithm(mdf1HashAlg) {
 		return fmt.Errorf("unrecognized hash algorithm %q specified as MDF1 hash algorithm", evJSON.MDF1HashAlg)
 	}
 
+	var policy []byte
+	if evJSON.Policy != nil {
+		if policy, err = canonicalPolicy(evJSON.Policy); err != nil {
+			return err
+		}
+	}
+
 	*ev = rsaOAEPEncryptedValue{
-		encrypted:   encrypted,
-		oaepHashAlg: oaepHashAlg,
-		mdf1HashAlg: mdf1HashAlg,
+		encrypted:    encrypted,
+		oaepHashAlg:  oaepHashAlg,
+		mdf1HashAlg:  mdf1HashAlg,
+		keyHint:      evJSON.KeyHint,
+		policyConfig: policy,
 	}
 	return nil
 }
 
 func (ev *rsaOAEPEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	if ev.policyConfig != nil {
+		return "", errPolicyNotEnforced
+	}
+	return ev.decryptWithContext(key, nil)
+}
+
+func (ev *rsaOAEPEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
+func (ev *rsaOAEPEncryptedValue) decryptWithContext(key KeyWithType, context []byte) (string, error) {
 	cipher := encryption.RSAOAEPCipherWithAlgorithms(ev.oaepHashAlg, ev.mdf1HashAlg)
-	decrypted, err := cipher.Decrypt(ev.encrypted, key.Key)
+	decrypted, err := cipher.DecryptWithLabel(ev.encrypted, additionalData(context, ev.policyConfig), key.Key)
 	return string(decrypted), err
 }
 
 func (ev *rsaOAEPEncryptedValue) ToSerializable() SerializedEncryptedValue {
-	return encryptedValToSerializable(ev)
+	return toSerializable(ev)
+}
+
+func (ev *rsaOAEPEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return encryptedValToSerializableBytes(ev)
+}
+
+func (ev *rsaOAEPEncryptedValue) KeyHint() (string, bool) {
+	return ev.keyHint, ev.keyHint != ""
+}
+
+func (ev *rsaOAEPEncryptedValue) setKeyHint(hint string) {
+	ev.keyHint = hint
+	ev.parsedJSON = parsedJSON{}
+}
+
+func (ev *rsaOAEPEncryptedValue) policy() []byte {
+	return ev.policyConfig
+}
+
+func (ev *rsaOAEPEncryptedValue) algorithm() AlgorithmType {
+	return RSA
+}
+
+// isSupportedHashAlgorithm returns true if the provided hash algorithm is one for which encryption.HashAlgorithm.Hash
+// returns a hash (rather than panicking).
+func isSupportedHashAlgorithm(alg encryption.HashAlgorithm) bool {
+	switch alg {
+	case encryption.SHA1, encryption.SHA256:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,62 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const encSignedPrefix = "encs:"
+
+// ErrInvalidSignature is returned by VerifyAndParse when the signature of a signed value is not valid for the provided
+// public key.
+var ErrInvalidSignature = errors.New("signature of signed encrypted value is not valid")
+
+// SignValue returns the signed form of the provided value, which is of the form "encs:<base64-signature>:<serialized>",
+// where <serialized> is the serialized form of the value (as returned by ToSerializable) and <base64-signature> is the
+// base64-encoded Ed25519 signature of <serialized> created using the provided private key. The signature allows
+// consumers to verify which producer created the value using VerifyAndParse. The signature covers the entire
+// serialized value, including its key hint and policy. Returns an error if the provided key is not a valid Ed25519
+// private key.
+func SignValue(ev EncryptedValue, signKey ed25519.PrivateKey) (string, error) {
+	if len(signKey) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("invalid Ed25519 private key: must be %d bytes, was %d", ed25519.PrivateKeySize, len(signKey))
+	}
+	serialized, err := ev.ToSerializableBytes()
+	if err != nil {
+		return "", err
+	}
+	signature := ed25519.Sign(signKey, serialized)
+	return encSignedPrefix + base64.StdEncoding.EncodeToString(signature) + ":" + string(serialized), nil
+}
+
+// VerifyAndParse verifies the signature of the provided signed value (as returned by SignValue) using the provided
+// public key and returns the EncryptedValue that it contains. Returns an error that wraps ErrInvalidSignature if the
+// signature is not valid, and an error if the provided string is not a signed value or if the value that it contains
+// cannot be parsed.
+func VerifyAndParse(s string, verifyKey ed25519.PublicKey) (EncryptedValue, error) {
+	if len(verifyKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 public key: must be %d bytes, was %d", ed25519.PublicKeySize, len(verifyKey))
+	}
+	if !strings.HasPrefix(s, encSignedPrefix) {
+		return nil, fmt.Errorf(`signed encrypted value must be of the form "%s<signature>:<encrypted value>"`, encSignedPrefix)
+	}
+	signatureB64, serialized, ok := strings.Cut(s[len(encSignedPrefix):], ":")
+	if !ok {
+		return nil, fmt.Errorf(`signed encrypted value must be of the form "%s<signature>:<encrypted value>"`, encSignedPrefix)
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base64-decode signature: %v", err)
+	}
+	if !ed25519.Verify(verifyKey, []byte(serialized), signature) {
+		return nil, ErrInvalidSignature
+	}
+	return NewEncryptedValue(serialized)
+}
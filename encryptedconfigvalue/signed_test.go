@@ -0,0 +1,80 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignValue(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	signed, err := encryptedconfigvalue.SignValue(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), privKey)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, "encs:"))
+	assert.True(t, strings.HasSuffix(signed, ":"+string(testAESEncryptedVal)))
+
+	ev, err := encryptedconfigvalue.VerifyAndParse(signed, pubKey)
+	require.NoError(t, err)
+	decrypted, err := ev.Decrypt(encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey))
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", decrypted)
+
+	_, err = encryptedconfigvalue.VerifyAndParse(signed, otherPubKey)
+	assert.True(t, errors.Is(err, encryptedconfigvalue.ErrInvalidSignature))
+
+	// replacing the value invalidates the signature
+	tampered := signed[:strings.LastIndex(signed, ":enc:")+1] + string(testRSAEncryptedVal)
+	_, err = encryptedconfigvalue.VerifyAndParse(tampered, pubKey)
+	assert.True(t, errors.Is(err, encryptedconfigvalue.ErrInvalidSignature))
+
+	// signed values cannot be parsed without verifying them
+	_, err = encryptedconfigvalue.NewEncryptedValue(signed)
+	assert.EqualError(t, err, `signed encrypted values of the form "encs:..." must be parsed using VerifyAndParse`)
+}
+
+func TestVerifyAndParseInvalid(t *testing.T) {
+	pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "not signed",
+			input:   string(testAESEncryptedVal),
+			wantErr: `signed encrypted value must be of the form "encs:<signature>:<encrypted value>"`,
+		},
+		{
+			name:    "missing value",
+			input:   "encs:AAAA",
+			wantErr: `signed encrypted value must be of the form "encs:<signature>:<encrypted value>"`,
+		},
+		{
+			name:    "invalid signature encoding",
+			input:   "encs:!!:" + string(testAESEncryptedVal),
+			wantErr: "failed to base64-decode signature: illegal base64 data at input byte 0",
+		},
+	} {
+		_, err := encryptedconfigvalue.VerifyAndParse(currCase.input, pubKey)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+	}
+
+	_, err = encryptedconfigvalue.SignValue(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), ed25519.PrivateKey("short"))
+	assert.EqualError(t, err, "invalid Ed25519 private key: must be 64 bytes, was 5")
+}
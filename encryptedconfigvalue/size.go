@@ -0,0 +1,80 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// sha256SizeBytes is the size of a SHA-256 digest, which is the OAEP hash of values encrypted using the default RSA
+// encrypter.
+const sha256SizeBytes = 32
+
+// SerializedSize returns the length of the serialized form (as returned by ToSerializable) of the value that results
+// from encrypting a plaintext of the provided length using the default Encrypter for the provided algorithm without
+// a key hint. The value is not encrypted. The size for AES includes the 96-bit nonce and 128-bit tag. The size for RSA
+// assumes a key of the default size (2048 bits), in which case the ciphertext is always 256 bytes. Returns -1 if the
+// size cannot be predicted: this is the case for RSA if the plaintext is too long to be encrypted using a key of the
+// default size (such plaintexts can be encrypted using envelope encryption, whose size is returned by
+// SerializedEnvelopeSize), and for algorithms other than AES and RSA.
+func SerializedSize(plaintextLen int, alg AlgorithmType) int {
+	if plaintextLen < 0 {
+		return -1
+	}
+	switch alg {
+	default:
+		return -1
+	case AES:
+		return serializedSizeOf(&aesGCMEncryptedValue{
+			nonce: make([]byte, aesGCMDefaultNonceSizeBytes),
+			tag:   make([]byte, aesGCMDefaultTagSizeBytes),
+		}, plaintextLen)
+	case RSA:
+		modulusLen := defaultRSAKeySizeBits / 8
+		if plaintextLen > modulusLen-2*sha256SizeBytes-2 {
+			return -1
+		}
+		return serializedSizeOf(&rsaOAEPEncryptedValue{
+			oaepHashAlg: rsaOAEPDefaultOAEPHash,
+			mdf1HashAlg: rsaOAEPDefaultMDF1Hash,
+		}, modulusLen)
+	}
+}
+
+// SerializedEnvelopeSize returns the length of the serialized form (as returned by ToSerializable) of the value that
+// results from encrypting a plaintext of the provided length using the Encrypter returned by NewRSAEnvelopeEncrypter
+// without a key hint. The value is not encrypted. The size includes the data key wrapped using an RSA key of the
+// default size (2048 bits), which is always 256 bytes, and the AES-GCM payload with its 96-bit nonce and 128-bit tag.
+// Unlike the size of RSA values returned by SerializedSize, the size can be predicted for plaintexts of any length.
+// Returns -1 if the provided length is negative.
+func SerializedEnvelopeSize(plaintextLen int) int {
+	if plaintextLen < 0 {
+		return -1
+	}
+	return serializedSizeOf(&rsaEnvelopeEncryptedValue{
+		oaepHashAlg: rsaOAEPDefaultOAEPHash,
+		mdf1HashAlg: rsaOAEPDefaultMDF1Hash,
+		payload: &aesGCMEncryptedValue{
+			nonce: make([]byte, aesGCMDefaultNonceSizeBytes),
+			tag:   make([]byte, aesGCMDefaultTagSizeBytes),
+		},
+	}, defaultRSAKeySizeBits/8, plaintextLen)
+}
+
+// serializedSizeOf returns the length of the serialized form of the provided value after its empty binary fields are
+// replaced with fields of the provided lengths. Returns -1 if the value cannot be marshaled.
+func serializedSizeOf(emptyValue EncryptedValue, fieldLens ...int) int {
+	jsonBytes, err := json.Marshal(emptyValue)
+	if err != nil {
+		return -1
+	}
+	// the empty fields are empty strings in the JSON, and base64 output does not require escaping in JSON
+	jsonLen := len(jsonBytes)
+	for _, fieldLen := range fieldLens {
+		jsonLen += base64.StdEncoding.EncodedLen(fieldLen)
+	}
+	return len(encPrefix) + base64.StdEncoding.EncodedLen(jsonLen)
+}
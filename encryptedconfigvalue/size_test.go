@@ -0,0 +1,46 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializedSize(t *testing.T) {
+	for _, currAlg := range []encryptedconfigvalue.AlgorithmType{
+		encryptedconfigvalue.AES,
+		encryptedconfigvalue.RSA,
+	} {
+		kp, err := currAlg.GenerateKeyPair()
+		require.NoError(t, err)
+		for _, plaintextLen := range []int{0, 1, 2, 3, 16, 100, 190} {
+			ev, err := currAlg.Encrypter().Encrypt(strings.Repeat("a", plaintextLen), kp.EncryptionKey)
+			require.NoError(t, err, "%s: %d", currAlg, plaintextLen)
+			assert.Equal(t, len(ev.ToSerializable()), encryptedconfigvalue.SerializedSize(plaintextLen, currAlg), "%s: %d", currAlg, plaintextLen)
+		}
+	}
+	assert.Equal(t, 7252, encryptedconfigvalue.SerializedSize(4000, encryptedconfigvalue.AES))
+
+	// plaintext is too long for the default RSA key size
+	assert.Equal(t, -1, encryptedconfigvalue.SerializedSize(191, encryptedconfigvalue.RSA))
+	assert.Equal(t, -1, encryptedconfigvalue.SerializedSize(10, encryptedconfigvalue.THRESHOLD))
+	assert.Equal(t, -1, encryptedconfigvalue.SerializedSize(-1, encryptedconfigvalue.AES))
+}
+
+func TestSerializedEnvelopeSize(t *testing.T) {
+	kp, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	for _, plaintextLen := range []int{0, 1, 2, 3, 16, 190, 191, 4000} {
+		ev, err := encryptedconfigvalue.NewRSAEnvelopeEncrypter().Encrypt(strings.Repeat("a", plaintextLen), kp.EncryptionKey)
+		require.NoError(t, err, "%d", plaintextLen)
+		assert.Equal(t, len(ev.ToSerializable()), encryptedconfigvalue.SerializedEnvelopeSize(plaintextLen), "%d", plaintextLen)
+	}
+	assert.Equal(t, -1, encryptedconfigvalue.SerializedEnvelopeSize(-1))
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientStrength is returned (wrapped) by an Encrypter returned by WithMinStrength when a value would be
+// encrypted using a key that is weaker than its minimum strength.
+var ErrInsufficientStrength = errors.New("encryption does not meet the minimum strength")
+
+// MinStrength is the minimum strength required by an Encrypter returned by WithMinStrength. A minimum of 0 means that
+// keys of the corresponding algorithm are not restricted.
+type MinStrength struct {
+	// AESKeySizeBits is the minimum size of AES keys (including the data keys of envelope encrypted values).
+	AESKeySizeBits int
+	// RSAKeySizeBits is the minimum size of the modulus of RSA keys.
+	RSAKeySizeBits int
+}
+
+type minStrengthEncrypter struct {
+	encrypter Encrypter
+	min       MinStrength
+}
+
+// WithMinStrength returns an Encrypter that encrypts values using the provided encrypter, but that returns an error
+// that wraps ErrInsufficientStrength before performing any encryption if the provided key is weaker than the provided
+// minimum strength. For example, a minimum RSA key size of 3072 rejects 2048-bit RSA keys, and a minimum AES key size
+// of 256 rejects 128-bit AES keys. If the provided encrypter is one returned by RSAEnvelopeEncrypterWithDataKeySize,
+// the size of the data keys that it generates is checked against the minimum AES key size as well.
+func WithMinStrength(encrypter Encrypter, min MinStrength) Encrypter {
+	return &minStrengthEncrypter{
+		encrypter: encrypter,
+		min:       min,
+	}
+}
+
+func (e *minStrengthEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	info := key.Describe()
+	var minBits int
+	switch info.Algorithm {
+	case AES:
+		minBits = e.min.AESKeySizeBits
+	case RSA:
+		minBits = e.min.RSAKeySizeBits
+	}
+	if info.SizeBits < minBits {
+		return nil, fmt.Errorf("%w: %s key is %d bits, but at least %d bits are required", ErrInsufficientStrength, info.Algorithm, info.SizeBits, minBits)
+	}
+	if envelopeEncrypter, ok := e.encrypter.(*rsaEnvelopeEncrypter); ok && envelopeEncrypter.dataKeySizeBits < e.min.AESKeySizeBits {
+		return nil, fmt.Errorf("%w: envelope data key is %d bits, but at least %d bits are required", ErrInsufficientStrength, envelopeEncrypter.dataKeySizeBits, e.min.AESKeySizeBits)
+	}
+	return e.encrypter.Encrypt(input, key)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMinStrength(t *testing.T) {
+	aes128Key, err := encryptedconfigvalue.NewAESKey(128)
+	require.NoError(t, err)
+	aes256Key, err := encryptedconfigvalue.NewAESKey(256)
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	min := encryptedconfigvalue.MinStrength{
+		AESKeySizeBits: 256,
+		RSAKeySizeBits: 3072,
+	}
+
+	for i, currCase := range []struct {
+		name      string
+		encrypter encryptedconfigvalue.Encrypter
+		min       encryptedconfigvalue.MinStrength
+		key       encryptedconfigvalue.KeyWithType
+		wantErr   string
+	}{
+		{
+			name:      "AES key meets minimum",
+			encrypter: encryptedconfigvalue.NewAESGCMEncrypter(),
+			min:       min,
+			key:       aes256Key,
+		},
+		{
+			name:      "AES key below minimum",
+			encrypter: encryptedconfigvalue.NewAESGCMEncrypter(),
+			min:       min,
+			key:       aes128Key,
+			wantErr:   "encryption does not meet the minimum strength: AES key is 128 bits, but at least 256 bits are required",
+		},
+		{
+			name:      "RSA key below minimum",
+			encrypter: encryptedconfigvalue.NewRSAOAEPEncrypter(),
+			min:       min,
+			key:       rsaKP.EncryptionKey,
+			wantErr:   "encryption does not meet the minimum strength: RSA key is 2048 bits, but at least 3072 bits are required",
+		},
+		{
+			name:      "RSA key without minimum",
+			encrypter: encryptedconfigvalue.NewRSAOAEPEncrypter(),
+			min: encryptedconfigvalue.MinStrength{
+				AESKeySizeBits: 256,
+			},
+			key: rsaKP.EncryptionKey,
+		},
+		{
+			name:      "envelope data key below minimum",
+			encrypter: encryptedconfigvalue.RSAEnvelopeEncrypterWithDataKeySize(128),
+			min: encryptedconfigvalue.MinStrength{
+				AESKeySizeBits: 256,
+				RSAKeySizeBits: 2048,
+			},
+			key:     rsaKP.EncryptionKey,
+			wantErr: "encryption does not meet the minimum strength: envelope data key is 128 bits, but at least 256 bits are required",
+		},
+		{
+			name:      "envelope data key meets minimum",
+			encrypter: encryptedconfigvalue.NewRSAEnvelopeEncrypter(),
+			min: encryptedconfigvalue.MinStrength{
+				AESKeySizeBits: 256,
+				RSAKeySizeBits: 2048,
+			},
+			key: rsaKP.EncryptionKey,
+		},
+	} {
+		ev, err := encryptedconfigvalue.WithMinStrength(currCase.encrypter, currCase.min).Encrypt("secret", currCase.key)
+		if currCase.wantErr != "" {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+			assert.True(t, errors.Is(err, encryptedconfigvalue.ErrInsufficientStrength), "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.NotNil(t, ev, "Case %d: %s", i, currCase.name)
+	}
+}
@@ -119,8 +119,9 @@ func NewStringVar(input string) StringVar {
 // variable (if it does not start with "${" and end with "}").
 func (sv StringVar) Contents() (string, error) {
 	inputStr := string(sv)
-	if !strings.HasPrefix(inputStr, `${`) && !strings.HasSuffix(inputStr, `}`) {
-		return "", fmt.Errorf("string variable must be of the form %q, was: %s", `${...}`, inputStr)
+	if len(inputStr) < len(`${}`) || !strings.HasPrefix(inputStr, `${`) || !strings.HasSuffix(inputStr, `}`) {
+		// the input is not included in the error because it may be a plaintext value
+		return "", fmt.Errorf("string variable must be of the form %q", `${...}`)
 	}
 	return inputStr[len(`${`) : len(inputStr)-len(`}`)], nil
 }
@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+// TelemetryAttribute* are the keys of the attributes returned by TelemetryAttributes.
+const (
+	TelemetryAttributeAlgorithm = "encrypted_config_value.algorithm"
+	TelemetryAttributeFormat    = "encrypted_config_value.format"
+	TelemetryAttributeMode      = "encrypted_config_value.mode"
+	TelemetryAttributeKeyHint   = "encrypted_config_value.key_hint"
+)
+
+// TelemetryAttributes returns the non-secret metadata of the provided value as a map of attributes that can be attached
+// to the spans or metrics of a telemetry library (for example, as OpenTelemetry attributes). The returned attributes
+// never contain the plaintext or the ciphertext of the value. The attributes are:
+//
+//   - TelemetryAttributeFormat: "legacy" for values in the legacy format and "new" otherwise
+//   - TelemetryAttributeAlgorithm: the algorithm of the value (omitted for values in the legacy format)
+//   - TelemetryAttributeMode: the mode of the value, such as "GCM", "GCM-SEGMENTED", "OAEP" or "ENVELOPE" (omitted for
+//     values in the legacy format)
+//   - TelemetryAttributeKeyHint: the key hint of the value (omitted if the value does not have a key hint)
+//
+// Values do not record the key that was used to encrypt them or the time at which they were created. The fingerprint
+// of the key that decrypts a value can be obtained using KeyWithType.Describe.
+func TelemetryAttributes(ev EncryptedValue) map[string]string {
+	meta := newValueMetadata("", ev)
+	attrs := map[string]string{
+		TelemetryAttributeFormat: "new",
+	}
+	if meta.Legacy {
+		attrs[TelemetryAttributeFormat] = "legacy"
+	} else {
+		attrs[TelemetryAttributeAlgorithm] = string(meta.Algorithm)
+	}
+	if mode := valueMode(ev); mode != "" {
+		attrs[TelemetryAttributeMode] = mode
+	}
+	if meta.KeyHint != "" {
+		attrs[TelemetryAttributeKeyHint] = meta.KeyHint
+	}
+	return attrs
+}
+
+// valueMode returns the mode stored in the serialized form of the provided value, or an empty string if the value does
+// not have a mode.
+func valueMode(ev EncryptedValue) string {
+	switch ev.(type) {
+	case *aesGCMEncryptedValue:
+		return gcmMode
+	case *aesGCMSegmentedEncryptedValue:
+		return gcmSegmentedMode
+	case *rsaOAEPEncryptedValue:
+		return oaepMode
+	case *rsaEnvelopeEncryptedValue:
+		return envelopeMode
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelemetryAttributes(t *testing.T) {
+	aesKey := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	hinted, err := encryptedconfigvalue.WithKeyHint(encryptedconfigvalue.NewAESGCMEncrypter(), "kms-1").Encrypt("secret", aesKey)
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	envelope, err := encryptedconfigvalue.NewRSAEnvelopeEncrypter().Encrypt("secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name string
+		ev   encryptedconfigvalue.EncryptedValue
+		want map[string]string
+	}{
+		{
+			name: "AES value",
+			ev:   encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal),
+			want: map[string]string{
+				encryptedconfigvalue.TelemetryAttributeFormat:    "new",
+				encryptedconfigvalue.TelemetryAttributeAlgorithm: "AES",
+				encryptedconfigvalue.TelemetryAttributeMode:      "GCM",
+			},
+		},
+		{
+			name: "AES value with key hint",
+			ev:   hinted,
+			want: map[string]string{
+				encryptedconfigvalue.TelemetryAttributeFormat:    "new",
+				encryptedconfigvalue.TelemetryAttributeAlgorithm: "AES",
+				encryptedconfigvalue.TelemetryAttributeMode:      "GCM",
+				encryptedconfigvalue.TelemetryAttributeKeyHint:   "kms-1",
+			},
+		},
+		{
+			name: "RSA value",
+			ev:   encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testRSAEncryptedVal),
+			want: map[string]string{
+				encryptedconfigvalue.TelemetryAttributeFormat:    "new",
+				encryptedconfigvalue.TelemetryAttributeAlgorithm: "RSA",
+				encryptedconfigvalue.TelemetryAttributeMode:      "OAEP",
+			},
+		},
+		{
+			name: "envelope value",
+			ev:   envelope,
+			want: map[string]string{
+				encryptedconfigvalue.TelemetryAttributeFormat:    "new",
+				encryptedconfigvalue.TelemetryAttributeAlgorithm: "RSA",
+				encryptedconfigvalue.TelemetryAttributeMode:      "ENVELOPE",
+			},
+		},
+		{
+			name: "legacy value",
+			ev:   encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal),
+			want: map[string]string{
+				encryptedconfigvalue.TelemetryAttributeFormat: "legacy",
+			},
+		},
+	} {
+		assert.Equal(t, currCase.want, encryptedconfigvalue.TelemetryAttributes(currCase.ev), "Case %d: %s", i, currCase.name)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+)
+
+// THRESHOLD is the AlgorithmType of values created by EncryptThreshold. It is not a general-purpose algorithm: there
+// is no key pair or Encrypter for it, so it is not recognized by ToAlgorithmType.
+const THRESHOLD = AlgorithmType("THRESHOLD")
+
+const thresholdDataKeySizeBits = 256
+
+// EncryptThreshold encrypts the provided plaintext such that at least threshold of the provided keys are required to
+// decrypt it. The plaintext is encrypted using AES-GCM with a random data key, and the data key is split into one
+// share per provided key using Shamir's secret sharing. Each share is encrypted using its key (using the Encrypter for
+// the algorithm type of the key), so the value can only be decrypted by providing the decryption keys for at least
+// threshold of the shares to DecryptThreshold. Returns an error if threshold is not between 1 and the number of keys
+// or if the same key is provided more than once.
+func EncryptThreshold(plaintext string, threshold int, keys ...KeyWithType) (EncryptedValue, error) {
+	seen := make(map[SerializedKeyWithType]bool)
+	for _, key := range keys {
+		serialized := key.ToSerializable()
+		if seen[serialized] {
+			return nil, fmt.Errorf("every key for a threshold value must be distinct")
+		}
+		seen[serialized] = true
+	}
+
+	dataKey, err := NewAESKey(thresholdDataKeySizeBits)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := NewAESGCMEncrypter().Encrypt(plaintext, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	dataKeyBytes := dataKey.Key.(*encryption.AESKey).Bytes()
+	secretShares, err := encryption.SplitSecret(dataKeyBytes, len(keys), threshold)
+	zeroBytes(dataKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]EncryptedValue, len(keys))
+	for i, key := range keys {
+		shares[i], err = key.Type.AlgorithmType().Encrypter().Encrypt(base64.StdEncoding.EncodeToString(secretShares[i]), key)
+		zeroBytes(secretShares[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt share %d: %v", i, err)
+		}
+	}
+	return &thresholdEncryptedValue{
+		threshold: threshold,
+		shares:    shares,
+		payload:   payload.(*aesGCMEncryptedValue),
+	}, nil
+}
+
+// DecryptThreshold decrypts a value created by EncryptThreshold using the provided keys. The keys may be provided in
+// any order and each key can be used to decrypt at most one share. Returns an error if the provided value was not
+// created by EncryptThreshold or if the provided keys cannot decrypt at least the threshold number of shares.
+func DecryptThreshold(ev EncryptedValue, keys ...KeyWithType) (string, error) {
+	thresholdEV, ok := ev.(*thresholdEncryptedValue)
+	if !ok {
+		return "", fmt.Errorf("value of type %T is not a threshold encrypted value", ev)
+	}
+	return thresholdEV.decrypt(keys)
+}
+
+// SealToSelfAndRecipient encrypts the provided plaintext such that it can be decrypted by either of the provided keys:
+// it is equivalent to EncryptThreshold with a threshold of 1 for the encryption keys of selfKey and recipientKey. Each
+// key may be an AES key, an RSA public key or an RSA private key, in which case the value is encrypted using the public
+// key of the private key. The returned value can be decrypted using Decrypt with the decryption key of either party.
+// Returns an error if both keys have the same encryption key.
+func SealToSelfAndRecipient(plaintext string, selfKey, recipientKey KeyWithType) (EncryptedValue, error) {
+	return EncryptThreshold(plaintext, 1, encryptionKeyOf(selfKey), encryptionKeyOf(recipientKey))
+}
+
+// encryptionKeyOf returns the key that should be used to encrypt values for the provided key: the public key of an RSA
+// private key, and the provided key otherwise.
+func encryptionKeyOf(key KeyWithType) KeyWithType {
+	if key.Type != RSAPrivKey {
+		return key
+	}
+	resolved, release, err := resolveKeyWithType(key)
+	if err != nil {
+		// encrypting using the provided key fails with an error that describes why it cannot be used
+		return key
+	}
+	defer release()
+	rsaPrivKey, ok := resolved.Key.(*encryption.RSAPrivateKey)
+	if !ok {
+		return key
+	}
+	// the public values of the key are not zeroed when it is released
+	return RSAPublicKeyFromKey((*encryption.RSAPublicKey)(&(*rsa.PrivateKey)(rsaPrivKey).PublicKey))
+}
+
+type thresholdEncryptedValue struct {
+	threshold int
+	shares    []EncryptedValue
+	payload   *aesGCMEncryptedValue
+	parsedJSON
+}
+
+type thresholdEncryptedValueJSON struct {
+	Type      string                     `json:"type"`
+	Threshold int                        `json:"threshold"`
+	Shares    []SerializedEncryptedValue `json:"shares"`
+	Payload   json.RawMessage            `json:"payload"`
+}
+
+func (ev thresholdEncryptedValue) MarshalJSON() ([]byte, error) {
+	shares := make([]SerializedEncryptedValue, len(ev.shares))
+	for i, share := range ev.shares {
+		serialized, err := share.ToSerializableBytes()
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = SerializedEncryptedValue(serialized)
+	}
+	payload, err := json.Marshal(ev.payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(thresholdEncryptedValueJSON{
+		Type:      string(THRESHOLD),
+		Threshold: ev.threshold,
+		Shares:    shares,
+		Payload:   payload,
+	})
+}
+
+func (ev *thresholdEncryptedValue) UnmarshalJSON(data []byte) error {
+	var evJSON thresholdEncryptedValueJSON
+	if err := json.Unmarshal(data, &evJSON); err != nil {
+		return err
+	}
+	if evJSON.Threshold < 1 || evJSON.Threshold > len(evJSON.Shares) {
+		return fmt.Errorf("threshold must be between 1 and the number of shares (%d), was %d", len(evJSON.Shares), evJSON.Threshold)
+	}
+	shares := make([]EncryptedValue, len(evJSON.Shares))
+	for i, serializedShare := range evJSON.Shares {
+		share, err := NewEncryptedValueFromSerialized(serializedShare)
+		if err != nil {
+			return fmt.Errorf("invalid share %d: %v", i, err)
+		}
+		if _, ok := share.(*thresholdEncryptedValue); ok {
+			return fmt.Errorf("invalid share %d: shares cannot be threshold encrypted values", i)
+		}
+		shares[i] = share
+	}
+	var payload aesGCMEncryptedValue
+	if err := json.Unmarshal(evJSON.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+	*ev = thresholdEncryptedValue{
+		threshold: evJSON.Threshold,
+		shares:    shares,
+		payload:   &payload,
+	}
+	return nil
+}
+
+// Decrypt decrypts the value using the provided key. This is only possible if the threshold of the value is 1: values
+// with a larger threshold must be decrypted using DecryptThreshold.
+func (ev *thresholdEncryptedValue) Decrypt(key KeyWithType) (string, error) {
+	if ev.threshold != 1 {
+		return "", fmt.Errorf("threshold encrypted value requires %d keys to decrypt: use DecryptThreshold", ev.threshold)
+	}
+	return ev.decrypt([]KeyWithType{key})
+}
+
+func (ev *thresholdEncryptedValue) DecryptValidUTF8(key KeyWithType) (string, error) {
+	return decryptValidUTF8(ev, key)
+}
+
+func (ev *thresholdEncryptedValue) decrypt(keys []KeyWithType) (string, error) {
+	usedKeys := make([]bool, len(keys))
+	var secretShares [][]byte
+	defer func() {
+		for _, share := range secretShares {
+			zeroBytes(share)
+		}
+	}()
+	for _, share := range ev.shares {
+		if len(secretShares) == ev.threshold {
+			break
+		}
+		for keyIdx, key := range keys {
+			if usedKeys[keyIdx] {
+				continue
+			}
+			decrypted, err := share.Decrypt(key)
+			if err != nil {
+				continue
+			}
+			secretShare, err := base64.StdEncoding.DecodeString(decrypted)
+			if err != nil {
+				continue
+			}
+			usedKeys[keyIdx] = true
+			secretShares = append(secretShares, secretShare)
+			break
+		}
+	}
+	if len(secretShares) < ev.threshold {
+		return "", fmt.Errorf("insufficient keys to decrypt threshold encrypted value: %d shares are required, but the provided keys decrypted %d", ev.threshold, len(secretShares))
+	}
+
+	dataKeyBytes, err := encryption.CombineShares(secretShares)
+	if err != nil {
+		return "", fmt.Errorf("failed to combine shares: %v", err)
+	}
+	defer zeroBytes(dataKeyBytes)
+	return ev.payload.Decrypt(AESKeyFromBytes(dataKeyBytes))
+}
+
+func (ev *thresholdEncryptedValue) ToSerializable() SerializedEncryptedValue {
+	return toSerializable(ev)
+}
+
+func (ev *thresholdEncryptedValue) ToSerializableBytes() ([]byte, error) {
+	return encryptedValToSerializableBytes(ev)
+}
+
+func (ev *thresholdEncryptedValue) KeyHint() (string, bool) {
+	return "", false
+}
+
+func (ev *thresholdEncryptedValue) algorithm() AlgorithmType {
+	return THRESHOLD
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptThreshold(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	otherAESKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	strangerKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	ev, err := encryptedconfigvalue.EncryptThreshold("secret", 2, aesKP.EncryptionKey, rsaKP.EncryptionKey, otherAESKP.EncryptionKey)
+	require.NoError(t, err)
+	assert.NoError(t, encryptedconfigvalue.RequireAlgorithm(ev, encryptedconfigvalue.THRESHOLD))
+
+	// value survives serialization round trip
+	ev, err = encryptedconfigvalue.NewEncryptedValueFromSerialized(ev.ToSerializable())
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name    string
+		keys    []encryptedconfigvalue.KeyWithType
+		wantErr string
+	}{
+		{
+			name: "first two keys",
+			keys: []encryptedconfigvalue.KeyWithType{aesKP.DecryptionKey, rsaKP.DecryptionKey},
+		},
+		{
+			name: "last two keys in reverse order",
+			keys: []encryptedconfigvalue.KeyWithType{otherAESKP.DecryptionKey, rsaKP.DecryptionKey},
+		},
+		{
+			name: "all keys and an unrelated key",
+			keys: []encryptedconfigvalue.KeyWithType{strangerKP.DecryptionKey, otherAESKP.DecryptionKey, rsaKP.DecryptionKey, aesKP.DecryptionKey},
+		},
+		{
+			name:    "single key",
+			keys:    []encryptedconfigvalue.KeyWithType{aesKP.DecryptionKey},
+			wantErr: "insufficient keys to decrypt threshold encrypted value: 2 shares are required, but the provided keys decrypted 1",
+		},
+		{
+			name:    "same key provided twice",
+			keys:    []encryptedconfigvalue.KeyWithType{aesKP.DecryptionKey, aesKP.DecryptionKey},
+			wantErr: "insufficient keys to decrypt threshold encrypted value: 2 shares are required, but the provided keys decrypted 1",
+		},
+		{
+			name:    "public key instead of private key",
+			keys:    []encryptedconfigvalue.KeyWithType{aesKP.DecryptionKey, rsaKP.EncryptionKey},
+			wantErr: "insufficient keys to decrypt threshold encrypted value: 2 shares are required, but the provided keys decrypted 1",
+		},
+	} {
+		decrypted, err := encryptedconfigvalue.DecryptThreshold(ev, currCase.keys...)
+		if currCase.wantErr != "" {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, "secret", decrypted, "Case %d: %s", i, currCase.name)
+	}
+
+	_, err = ev.Decrypt(aesKP.DecryptionKey)
+	assert.EqualError(t, err, "threshold encrypted value requires 2 keys to decrypt: use DecryptThreshold")
+}
+
+func TestEncryptThresholdOfOne(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	ev, err := encryptedconfigvalue.EncryptThreshold("secret", 1, aesKP.EncryptionKey, rsaKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, key := range []encryptedconfigvalue.KeyWithType{aesKP.DecryptionKey, rsaKP.DecryptionKey} {
+		decrypted, err := ev.Decrypt(key)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, "secret", decrypted, "Case %d", i)
+	}
+}
+
+func TestEncryptThresholdErrors(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	otherAESKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = encryptedconfigvalue.EncryptThreshold("secret", 3, aesKP.EncryptionKey, otherAESKP.EncryptionKey)
+	assert.EqualError(t, err, "threshold must be between 1 and the number of shares (2), was 3")
+
+	_, err = encryptedconfigvalue.EncryptThreshold("secret", 2, aesKP.EncryptionKey, aesKP.EncryptionKey)
+	assert.EqualError(t, err, "every key for a threshold value must be distinct")
+
+	_, err = encryptedconfigvalue.EncryptThreshold("secret", 1)
+	assert.EqualError(t, err, "number of shares must be between 1 and 255, was 0")
+
+	_, err = encryptedconfigvalue.DecryptThreshold(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal), aesKP.DecryptionKey)
+	assert.EqualError(t, err, "value of type *encryptedconfigvalue.aesGCMEncryptedValue is not a threshold encrypted value")
+}
+
+func TestSealToSelfAndRecipient(t *testing.T) {
+	selfKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	recipientKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	otherKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// the private key of the producing service can be provided in place of its public key
+	ev, err := encryptedconfigvalue.SealToSelfAndRecipient("secret", selfKP.DecryptionKey, recipientKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, key := range []encryptedconfigvalue.KeyWithType{selfKP.DecryptionKey, recipientKP.DecryptionKey} {
+		decrypted, err := ev.Decrypt(key)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, "secret", decrypted, "Case %d", i)
+	}
+	_, err = ev.Decrypt(otherKP.DecryptionKey)
+	assert.Error(t, err)
+
+	_, err = encryptedconfigvalue.SealToSelfAndRecipient("secret", selfKP.DecryptionKey, selfKP.EncryptionKey)
+	assert.EqualError(t, err, "every key for a threshold value must be distinct")
+}
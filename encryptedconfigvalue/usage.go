@@ -0,0 +1,54 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"sync"
+)
+
+type usageLimitEncrypter struct {
+	encrypter   Encrypter
+	threshold   uint64
+	onThreshold func(key KeyInfo, count uint64)
+
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// WithUsageLimit returns an Encrypter that encrypts values using the provided encrypter and counts the number of values
+// that it has successfully encrypted using each key (keys are identified by their fingerprint). When the count for a
+// key reaches the provided threshold, onThreshold is called once with the metadata of the key and the count, which
+// can be used to signal that the key should be rotated (for example, because the number of values encrypted using an
+// AES-GCM key with random nonces should be bounded). Encryption is never prevented: values continue to be encrypted
+// after the threshold is reached. onThreshold is called synchronously by the Encrypt call that reaches the threshold.
+// A threshold of 0 disables the callback.
+//
+// The counts are held in memory by the returned Encrypter, which is safe for concurrent use. They are not shared with
+// other Encrypters and are not persisted: callers that need to track usage across Encrypter instances or process
+// restarts are responsible for persisting the counts themselves.
+func WithUsageLimit(encrypter Encrypter, threshold uint64, onThreshold func(key KeyInfo, count uint64)) Encrypter {
+	return &usageLimitEncrypter{
+		encrypter:   encrypter,
+		threshold:   threshold,
+		onThreshold: onThreshold,
+		counts:      make(map[string]uint64),
+	}
+}
+
+func (e *usageLimitEncrypter) Encrypt(input string, key KeyWithType) (EncryptedValue, error) {
+	ev, err := e.encrypter.Encrypt(input, key)
+	if err != nil {
+		return nil, err
+	}
+	info := key.Describe()
+	e.mu.Lock()
+	e.counts[info.Fingerprint]++
+	count := e.counts[info.Fingerprint]
+	e.mu.Unlock()
+	if e.threshold != 0 && count == e.threshold && e.onThreshold != nil {
+		e.onThreshold(info, count)
+	}
+	return ev, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithUsageLimit(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var reached []encryptedconfigvalue.KeyInfo
+	encrypter := encryptedconfigvalue.WithUsageLimit(encryptedconfigvalue.NewAESGCMEncrypter(), 3, func(key encryptedconfigvalue.KeyInfo, count uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, uint64(3), count)
+		reached = append(reached, key)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := encrypter.Encrypt("secret", kp.EncryptionKey)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 2; i++ {
+		_, err := encrypter.Encrypt("secret", otherKP.EncryptionKey)
+		require.NoError(t, err)
+	}
+	// failed encryptions are not counted
+	for i := 0; i < 3; i++ {
+		_, err := encrypter.Encrypt("secret", rsaKP.EncryptionKey)
+		require.Error(t, err)
+	}
+
+	// the callback is called once for the key that reached the threshold
+	require.Len(t, reached, 1)
+	assert.Equal(t, kp.EncryptionKey.Describe(), reached[0])
+}
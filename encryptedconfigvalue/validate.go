@@ -0,0 +1,239 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrLegacyAlgorithmUnknown is returned by functions that need to determine the algorithm of an EncryptedValue without
+// decrypting it when the value is in the legacy format. Legacy values do not record the algorithm that was used to
+// encrypt them.
+var ErrLegacyAlgorithmUnknown = errors.New("the algorithm of a legacy encrypted value cannot be determined without decrypting it")
+
+// algorithmValue is implemented by EncryptedValue implementations that record the algorithm used to encrypt them.
+type algorithmValue interface {
+	algorithm() AlgorithmType
+}
+
+// RequireAlgorithm returns an error if the provided value was not encrypted using the provided algorithm. The value is
+// not decrypted. Returns ErrLegacyAlgorithmUnknown if the provided value is in the legacy format.
+func RequireAlgorithm(ev EncryptedValue, alg AlgorithmType) error {
+	algValue, ok := ev.(algorithmValue)
+	if !ok {
+		return ErrLegacyAlgorithmUnknown
+	}
+	if actual := algValue.algorithm(); actual != alg {
+		return fmt.Errorf("value must be encrypted using algorithm %s, but was encrypted using %s", alg, actual)
+	}
+	return nil
+}
+
+// ErrMultipleKeyAlgorithms is returned by RequiredKeyAlgorithm for THRESHOLD values whose shares are encrypted for keys
+// of different algorithms, so that no single key algorithm is required to decrypt them.
+var ErrMultipleKeyAlgorithms = errors.New("the shares of the threshold encrypted value are encrypted for keys of different algorithms")
+
+// RequiredKeyAlgorithm returns the algorithm of the key that is required to decrypt the provided serialized encrypted
+// value, which may be of the form "enc:...", "encc:..." or "encs:...". The returned algorithm is always AES or RSA:
+// AES-GCM segmented values require an AES key, so AES is returned for them, and RSA envelope values require an RSA
+// private key, so RSA is returned for them. THRESHOLD values are decrypted using the keys of their recipients, so the
+// algorithm of the keys for which their shares are encrypted is returned, or ErrMultipleKeyAlgorithms if the shares are
+// encrypted for keys of different algorithms. Only the algorithm type of the value (and of the shares of THRESHOLD
+// values) is read: the rest of the value is not parsed or validated, and the signature of "encs:..." values is not
+// verified. Returns ErrLegacyAlgorithmUnknown if the provided value is in the legacy format, and an error if the
+// algorithm type of the value cannot be read or is not recognized. If the value has a checksum suffix, the checksum is
+// verified and ErrChecksumMismatch is returned if it does not match.
+func RequiredKeyAlgorithm(s string) (AlgorithmType, error) {
+	s, err := stripChecksum(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(s, encSignedPrefix) {
+		if _, serialized, ok := strings.Cut(s[len(encSignedPrefix):], ":"); ok {
+			return RequiredKeyAlgorithm(serialized)
+		}
+		return "", fmt.Errorf(`signed encrypted value must be of the form "%s<signature>:<encrypted value>"`, encSignedPrefix)
+	}
+
+	var alg AlgorithmType
+	switch {
+	default:
+		return "", fmt.Errorf(`encrypted value must be of the form "%s..." or "%s..."`, encPrefix, encCBORPrefix)
+	case strings.HasPrefix(s, encCBORPrefix):
+		content, err := base64.StdEncoding.DecodeString(s[len(encCBORPrefix):])
+		if err != nil {
+			return "", fmt.Errorf("failed to base64-decode content: %v", err)
+		}
+		m, err := decodeCBORMap(content)
+		if err != nil {
+			return "", err
+		}
+		algStr, _ := m["type"].(string)
+		alg = AlgorithmType(algStr)
+	case strings.HasPrefix(s, encPrefix):
+		content, err := base64.StdEncoding.DecodeString(s[len(encPrefix):])
+		if err != nil {
+			return "", fmt.Errorf("failed to base64-decode content: %v", err)
+		}
+		var val struct {
+			Algorithm AlgorithmType              `json:"type"`
+			Shares    []SerializedEncryptedValue `json:"shares"`
+		}
+		if err := json.Unmarshal(content, &val); err != nil {
+			// value is not JSON: it is a legacy encrypted-value
+			return "", ErrLegacyAlgorithmUnknown
+		}
+		if val.Algorithm == THRESHOLD {
+			return thresholdSharesKeyAlgorithm(val.Shares)
+		}
+		alg = val.Algorithm
+	}
+	switch alg {
+	case AES, RSA:
+		return alg, nil
+	default:
+		return "", fmt.Errorf("unrecognized algorithm type: %s", alg)
+	}
+}
+
+// thresholdSharesKeyAlgorithm returns the algorithm of the keys for which the provided shares of a THRESHOLD value are
+// encrypted. Returns ErrMultipleKeyAlgorithms if the shares are encrypted for keys of different algorithms.
+func thresholdSharesKeyAlgorithm(shares []SerializedEncryptedValue) (AlgorithmType, error) {
+	if len(shares) == 0 {
+		return "", fmt.Errorf("threshold encrypted value does not have any shares")
+	}
+	var alg AlgorithmType
+	for i, share := range shares {
+		shareAlg, err := RequiredKeyAlgorithm(string(share))
+		if err != nil {
+			return "", fmt.Errorf("failed to determine the key algorithm of share %d: %v", i, err)
+		}
+		if alg != "" && shareAlg != alg {
+			return "", ErrMultipleKeyAlgorithms
+		}
+		alg = shareAlg
+	}
+	return alg, nil
+}
+
+// HasMixedFormats returns true if the provided JSON document contains both values in the legacy format and values in the
+// new format, which typically indicates that a migration from the legacy format is incomplete. Every string value of
+// the form "enc:...", "encc:..." or "encs:..." is considered: "encc:..." values are always in the new format, and the
+// format of "encs:..." values is the format of the value that is signed. Values are not decrypted and signatures are
+// not verified. Returns an error if the provided document is not valid JSON or if the format of a value cannot be
+// determined.
+func HasMixedFormats(data []byte) (bool, error) {
+	var hasLegacy, hasNew bool
+	if err := walkJSONStringValues(data, func(node jsonStringValue) error {
+		if !isEncryptedValueString(node.value) {
+			return nil
+		}
+		if _, err := RequiredKeyAlgorithm(node.value); err == ErrLegacyAlgorithmUnknown {
+			hasLegacy = true
+		} else if err != nil && err != ErrMultipleKeyAlgorithms {
+			// ErrMultipleKeyAlgorithms is only returned for THRESHOLD values, which are in the new format
+			return fmt.Errorf("failed to determine format of encrypted value at %q: %v", node.path, err)
+		} else {
+			hasNew = true
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	return hasLegacy && hasNew, nil
+}
+
+// IsDoublyEncrypted returns true if the plaintext of the provided value is itself an encrypted value (that is, if it
+// begins with "enc:", "encc:" or "encs:"). This typically indicates that a value was accidentally encrypted twice. The
+// provided key is used to decrypt the outer value only. Returns an error if the provided value cannot be decrypted
+// using the provided key.
+func IsDoublyEncrypted(ev EncryptedValue, key KeyWithType) (bool, error) {
+	decrypted, err := ev.Decrypt(key)
+	if err != nil {
+		return false, err
+	}
+	return isEncryptedValueString(decrypted), nil
+}
+
+// ErrKeyVerificationFailed is returned by VerifyKeyReference if the provided key is not the expected key.
+var ErrKeyVerificationFailed = errors.New("key verification failed")
+
+// VerifyKeyReference verifies that the provided key is the expected key by decrypting the provided canary value (a
+// value that was encrypted using the expected key) and comparing the result with the expected plaintext. As in
+// MatchesPlaintext, the SHA-256 digests of the two are compared in constant time, so the time taken by the comparison
+// reveals neither the contents nor the length of the expected plaintext, and the decrypted bytes are zeroed before
+// returning. Returns nil if the key is the expected key. Otherwise, returns an error that wraps
+// ErrKeyVerificationFailed. The returned error never contains the decrypted plaintext or the expected plaintext.
+func VerifyKeyReference(key KeyWithType, canary EncryptedValue, expectedPlaintext string) error {
+	decrypted, err := DecryptBytes(canary, key)
+	if err != nil {
+		return fmt.Errorf("%w: canary value could not be decrypted using the key: %v", ErrKeyVerificationFailed, err)
+	}
+	defer zeroBytes(decrypted)
+	if !digestsEqual(decrypted, []byte(expectedPlaintext)) {
+		return fmt.Errorf("%w: canary value did not decrypt to the expected plaintext", ErrKeyVerificationFailed)
+	}
+	return nil
+}
+
+// MatchesPlaintext returns true if the result of decrypting the provided value using the provided key is equal to the
+// provided candidate. The decrypted plaintext is never returned. The SHA-256 digests of the plaintext and the candidate
+// are compared in constant time, so the time taken by the comparison reveals neither the contents nor the length of
+// either. The decrypted bytes used for the comparison are zeroed before returning; the string returned by the
+// Decrypt function of the value cannot be zeroed and is left to the garbage collector. Returns an error if the
+// provided value cannot be decrypted using the provided key. The returned error never contains the decrypted
+// plaintext or the candidate.
+func MatchesPlaintext(ev EncryptedValue, key KeyWithType, candidate string) (bool, error) {
+	decrypted, err := DecryptBytes(ev, key)
+	if err != nil {
+		return false, err
+	}
+	defer zeroBytes(decrypted)
+	return digestsEqual(decrypted, []byte(candidate)), nil
+}
+
+// digestsEqual returns true if the SHA-256 digests of the provided byte slices are equal. The digests are compared in
+// constant time, so the time taken reveals neither the contents nor the lengths of the provided slices.
+func digestsEqual(a, b []byte) bool {
+	aDigest := sha256.Sum256(a)
+	bDigest := sha256.Sum256(b)
+	return subtle.ConstantTimeCompare(aDigest[:], bDigest[:]) == 1
+}
+
+// VerifyArchive reads newline-delimited serialized encrypted values from the provided reader and verifies that each of
+// them can be parsed and decrypted using the provided key. Returns the number of values that were verified
+// successfully and the total number of values. Blank lines are ignored. The decrypted plaintext of every value is
+// discarded immediately and the archive is processed one line at a time, so memory use is bounded by the size of the
+// largest value rather than the size of the archive. Returns an error only if reading from the reader fails; values
+// that fail verification are reflected in the returned counts.
+func VerifyArchive(r io.Reader, key KeyWithType) (valid, total int, err error) {
+	br := bufio.NewReader(r)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return valid, total, fmt.Errorf("failed to read archive: %v", readErr)
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			total++
+			if ev, err := NewEncryptedValue(string(line)); err == nil {
+				if _, err := ev.Decrypt(key); err == nil {
+					valid++
+				}
+			}
+		}
+		if readErr == io.EOF {
+			return valid, total, nil
+		}
+	}
+}
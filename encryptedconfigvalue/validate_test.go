@@ -0,0 +1,307 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryptedconfigvalue_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/palantir/go-encrypted-config-value/encryptedconfigvalue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDoublyEncrypted(t *testing.T) {
+	kp, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	encrypter := encryptedconfigvalue.AES.Encrypter()
+
+	once, err := encrypter.Encrypt("secret", kp.EncryptionKey)
+	require.NoError(t, err)
+	twice, err := encrypter.Encrypt(string(once.ToSerializable()), kp.EncryptionKey)
+	require.NoError(t, err)
+
+	doubly, err := encryptedconfigvalue.IsDoublyEncrypted(once, kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.False(t, doubly)
+
+	doubly, err = encryptedconfigvalue.IsDoublyEncrypted(twice, kp.DecryptionKey)
+	require.NoError(t, err)
+	assert.True(t, doubly)
+
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.IsDoublyEncrypted(twice, otherKP.DecryptionKey)
+	assert.Error(t, err)
+}
+
+func TestRequireAlgorithm(t *testing.T) {
+	for i, currCase := range []struct {
+		name    string
+		ev      encryptedconfigvalue.SerializedEncryptedValue
+		alg     encryptedconfigvalue.AlgorithmType
+		wantErr string
+	}{
+		{
+			name: "AES value requiring AES",
+			ev:   testAESEncryptedVal,
+			alg:  encryptedconfigvalue.AES,
+		},
+		{
+			name: "RSA value requiring RSA",
+			ev:   testRSAEncryptedVal,
+			alg:  encryptedconfigvalue.RSA,
+		},
+		{
+			name:    "RSA value requiring AES",
+			ev:      testRSAEncryptedVal,
+			alg:     encryptedconfigvalue.AES,
+			wantErr: "value must be encrypted using algorithm AES, but was encrypted using RSA",
+		},
+	} {
+		err := encryptedconfigvalue.RequireAlgorithm(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(currCase.ev), currCase.alg)
+		if currCase.wantErr == "" {
+			assert.NoError(t, err, "Case %d: %s", i, currCase.name)
+		} else {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+		}
+	}
+
+	err := encryptedconfigvalue.RequireAlgorithm(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(javaLegacyAESEncryptedVal), encryptedconfigvalue.AES)
+	assert.Equal(t, encryptedconfigvalue.ErrLegacyAlgorithmUnknown, err)
+}
+
+func TestRequiredKeyAlgorithm(t *testing.T) {
+	cborAES, err := encryptedconfigvalue.ToSerializableCBOR(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal))
+	require.NoError(t, err)
+	_, signKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signedRSA, err := encryptedconfigvalue.SignValue(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testRSAEncryptedVal), signKey)
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaEnvelopeVal, err := encryptedconfigvalue.NewRSAEnvelopeEncrypter().Encrypt("secret", rsaKP.EncryptionKey)
+	require.NoError(t, err)
+	aesKP1, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	aesKP2, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	aesThresholdVal, err := encryptedconfigvalue.EncryptThreshold("secret", 1, aesKP1.EncryptionKey, aesKP2.EncryptionKey)
+	require.NoError(t, err)
+	mixedThresholdVal, err := encryptedconfigvalue.EncryptThreshold("secret", 1, aesKP1.EncryptionKey, rsaKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name    string
+		input   string
+		want    encryptedconfigvalue.AlgorithmType
+		wantErr string
+	}{
+		{
+			name:  "AES value",
+			input: string(testAESEncryptedVal),
+			want:  encryptedconfigvalue.AES,
+		},
+		{
+			name:  "RSA value",
+			input: string(testRSAEncryptedVal),
+			want:  encryptedconfigvalue.RSA,
+		},
+		{
+			name:  "CBOR AES value",
+			input: string(cborAES),
+			want:  encryptedconfigvalue.AES,
+		},
+		{
+			name:  "signed RSA value",
+			input: signedRSA,
+			want:  encryptedconfigvalue.RSA,
+		},
+		{
+			name:  "RSA envelope value",
+			input: string(rsaEnvelopeVal.ToSerializable()),
+			want:  encryptedconfigvalue.RSA,
+		},
+		{
+			name:  "threshold value with AES shares",
+			input: string(aesThresholdVal.ToSerializable()),
+			want:  encryptedconfigvalue.AES,
+		},
+		{
+			name:    "threshold value with AES and RSA shares",
+			input:   string(mixedThresholdVal.ToSerializable()),
+			wantErr: encryptedconfigvalue.ErrMultipleKeyAlgorithms.Error(),
+		},
+		{
+			name:    "legacy value",
+			input:   string(javaLegacyAESEncryptedVal),
+			wantErr: encryptedconfigvalue.ErrLegacyAlgorithmUnknown.Error(),
+		},
+		{
+			name:    "unknown algorithm",
+			input:   "enc:" + base64.StdEncoding.EncodeToString([]byte(`{"type":"ROT13"}`)),
+			wantErr: "unrecognized algorithm type: ROT13",
+		},
+		{
+			name:    "not an encrypted value",
+			input:   "plaintext",
+			wantErr: `encrypted value must be of the form "enc:..." or "encc:..."`,
+		},
+	} {
+		alg, err := encryptedconfigvalue.RequiredKeyAlgorithm(currCase.input)
+		if currCase.wantErr == "" {
+			require.NoError(t, err, "Case %d: %s", i, currCase.name)
+			assert.Equal(t, currCase.want, alg, "Case %d: %s", i, currCase.name)
+		} else {
+			assert.EqualError(t, err, currCase.wantErr, "Case %d: %s", i, currCase.name)
+		}
+	}
+}
+
+func TestVerifyKeyReference(t *testing.T) {
+	canary := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name     string
+		key      encryptedconfigvalue.KeyWithType
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "expected key",
+			key:      encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey),
+			expected: "plaintext",
+		},
+		{
+			name:     "unexpected plaintext",
+			key:      encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey),
+			expected: "other",
+			wantErr:  "key verification failed: canary value did not decrypt to the expected plaintext",
+		},
+		{
+			name:     "unexpected plaintext of the same length",
+			key:      encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey),
+			expected: "plaintexT",
+			wantErr:  "key verification failed: canary value did not decrypt to the expected plaintext",
+		},
+		{
+			name:     "wrong key",
+			key:      otherKP.DecryptionKey,
+			expected: "plaintext",
+			wantErr:  "key verification failed: canary value could not be decrypted using the key",
+		},
+	} {
+		err := encryptedconfigvalue.VerifyKeyReference(currCase.key, canary, currCase.expected)
+		if currCase.wantErr == "" {
+			assert.NoError(t, err, "Case %d: %s", i, currCase.name)
+			continue
+		}
+		require.Error(t, err, "Case %d: %s", i, currCase.name)
+		assert.True(t, errors.Is(err, encryptedconfigvalue.ErrKeyVerificationFailed), "Case %d: %s", i, currCase.name)
+		assert.Contains(t, err.Error(), currCase.wantErr, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestMatchesPlaintext(t *testing.T) {
+	ev := encryptedconfigvalue.MustNewEncryptedValueFromSerialized(testAESEncryptedVal)
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+
+	for i, currCase := range []struct {
+		name      string
+		candidate string
+		want      bool
+	}{
+		{"equal", "plaintext", true},
+		{"different", "plaintexx", false},
+		{"prefix", "plain", false},
+		{"longer", "plaintext ", false},
+		{"empty", "", false},
+	} {
+		got, err := encryptedconfigvalue.MatchesPlaintext(ev, key, currCase.candidate)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, got, "Case %d: %s", i, currCase.name)
+	}
+
+	otherKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	_, err = encryptedconfigvalue.MatchesPlaintext(ev, otherKP.DecryptionKey, "plaintext")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "plaintext")
+}
+
+func TestVerifyArchive(t *testing.T) {
+	key := encryptedconfigvalue.MustNewKeyWithTypeFromSerialized(testAESEncryptedValKey)
+	archive := strings.Join([]string{
+		string(testAESEncryptedVal),
+		"",
+		string(testRSAEncryptedVal),
+		"enc:corrupt",
+		"plaintext",
+		string(testAESEncryptedVal) + "\r",
+		string(testAESEncryptedVal),
+	}, "\n")
+
+	valid, total, err := encryptedconfigvalue.VerifyArchive(strings.NewReader(archive), key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, valid)
+	assert.Equal(t, 6, total)
+
+	valid, total, err = encryptedconfigvalue.VerifyArchive(strings.NewReader(""), key)
+	require.NoError(t, err)
+	assert.Equal(t, 0, valid)
+	assert.Equal(t, 0, total)
+
+	_, _, err = encryptedconfigvalue.VerifyArchive(iotest.ErrReader(errors.New("disk failure")), key)
+	assert.EqualError(t, err, "failed to read archive: disk failure")
+}
+
+func TestHasMixedFormats(t *testing.T) {
+	aesKP, err := encryptedconfigvalue.AES.GenerateKeyPair()
+	require.NoError(t, err)
+	rsaKP, err := encryptedconfigvalue.RSA.GenerateKeyPair()
+	require.NoError(t, err)
+	mixedThresholdVal, err := encryptedconfigvalue.EncryptThreshold("secret", 1, aesKP.EncryptionKey, rsaKP.EncryptionKey)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		name   string
+		values []string
+		want   bool
+	}{
+		{"no values", nil, false},
+		{"only new format", []string{string(testAESEncryptedVal), string(testRSAEncryptedVal)}, false},
+		{"only legacy format", []string{string(javaLegacyAESEncryptedVal), string(javaLegacyRSAEncryptedVal)}, false},
+		{"mixed formats", []string{string(testAESEncryptedVal), "plaintext", string(javaLegacyAESEncryptedVal)}, true},
+		{"legacy and compact formats", []string{string(javaLegacyAESEncryptedVal), mustToSerializableCBOR(t, testAESEncryptedVal)}, true},
+		{"legacy and threshold formats", []string{string(javaLegacyAESEncryptedVal), string(mixedThresholdVal.ToSerializable())}, true},
+	} {
+		doc, err := json.Marshal(map[string]interface{}{
+			"values": currCase.values,
+		})
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		got, err := encryptedconfigvalue.HasMixedFormats(doc)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, got, "Case %d: %s", i, currCase.name)
+	}
+
+	_, err = encryptedconfigvalue.HasMixedFormats([]byte(`{"a": "enc:!!!"}`))
+	assert.EqualError(t, err, `failed to determine format of encrypted value at "/a": failed to base64-decode content: illegal base64 data at input byte 0`)
+	_, err = encryptedconfigvalue.HasMixedFormats([]byte(`{"a": `))
+	assert.Error(t, err)
+}
+
+func mustToSerializableCBOR(t *testing.T, serialized encryptedconfigvalue.SerializedEncryptedValue) string {
+	cbor, err := encryptedconfigvalue.ToSerializableCBOR(encryptedconfigvalue.MustNewEncryptedValueFromSerialized(serialized))
+	require.NoError(t, err)
+	return string(cbor)
+}
@@ -42,6 +42,18 @@ func AESGCMCipherWithNonceAndTagSize(nonceSizeBytes, tagSizeBytes int) *AESGCMCi
 // Encrypt encrypts the provided value using the specified key. The key must be of type *AESKey. The returned bytes are
 // [nonce+ciphertext+tag].
 func (a *AESGCMCipher) Encrypt(data []byte, key Key) ([]byte, error) {
+	return a.EncryptWithAdditionalData(data, nil, key)
+}
+
+// EncryptWithAdditionalData behaves like Encrypt, but also authenticates the provided additional data, which is not
+// encrypted and is not included in the output. The same additional data must be provided to DecryptWithAdditionalData
+// to decrypt the output.
+func (a *AESGCMCipher) EncryptWithAdditionalData(data, additionalData []byte, key Key) ([]byte, error) {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	aesKey, ok := key.(*AESKey)
 	if !ok {
 		return nil, fmt.Errorf("key must be of *AESKey, but was %T", key)
@@ -58,7 +70,7 @@ func (a *AESGCMCipher) Encrypt(data []byte, key Key) ([]byte, error) {
 		return nil, fmt.Errorf("failed to generate nonce: %v", err)
 	}
 
-	encrypted := gcm.Seal(nil, nonce, data, nil)
+	encrypted := gcm.Seal(nil, nonce, data, additionalData)
 
 	return append(nonce, encrypted...), nil
 }
@@ -75,6 +87,17 @@ func (a *AESGCMCipher) Parts(encryptedData []byte) (nonce []byte, ciphertext []b
 // be of the form [nonce+ciphertext+tag]. Returns the bytes for the decrypted ciphertext (the input originally provided
 // to Encrypt).
 func (a *AESGCMCipher) Decrypt(data []byte, key Key) ([]byte, error) {
+	return a.DecryptWithAdditionalData(data, nil, key)
+}
+
+// DecryptWithAdditionalData behaves like Decrypt, but also verifies the provided additional data, which must be the
+// additional data that was provided to EncryptWithAdditionalData.
+func (a *AESGCMCipher) DecryptWithAdditionalData(data, additionalData []byte, key Key) ([]byte, error) {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	aesKey, ok := key.(*AESKey)
 	if !ok {
 		return nil, fmt.Errorf("key must be of type *AESKey, was %T", key)
@@ -84,7 +107,7 @@ func (a *AESGCMCipher) Decrypt(data []byte, key Key) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	plain, err := gcm.Open(nil, data[:a.nonceSizeBytes], data[a.nonceSizeBytes:], nil)
+	plain, err := gcm.Open(nil, data[:a.nonceSizeBytes], data[a.nonceSizeBytes:], additionalData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt value: %v", err)
 	}
@@ -34,3 +34,55 @@ func TestAESEncryptDecrypt(t *testing.T) {
 		assert.Equal(t, currCase.input, decrypted, "Case %d: %s", i, currCase.name)
 	}
 }
+
+func TestAESEncryptDecryptWithAdditionalData(t *testing.T) {
+	aesKey, err := encryption.NewAESKey(256)
+	require.NoError(t, err)
+
+	cipher := encryption.NewAESGCMCipher()
+	encrypted, err := cipher.EncryptWithAdditionalData([]byte("secret message"), []byte("context"), aesKey)
+	require.NoError(t, err)
+
+	decrypted, err := cipher.DecryptWithAdditionalData(encrypted, []byte("context"), aesKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret message"), decrypted)
+
+	_, err = cipher.DecryptWithAdditionalData(encrypted, []byte("other"), aesKey)
+	assert.Error(t, err)
+	_, err = cipher.Decrypt(encrypted, aesKey)
+	assert.Error(t, err)
+}
+
+type testKeyResolver struct {
+	key      *encryption.AESKey
+	resolved int
+	released int
+}
+
+func (r *testKeyResolver) Bytes() []byte {
+	return r.key.Bytes()
+}
+
+func (r *testKeyResolver) ResolveKey() (encryption.Key, func(), error) {
+	r.resolved++
+	return r.key, func() {
+		r.released++
+	}, nil
+}
+
+func TestAESEncryptDecryptWithKeyResolver(t *testing.T) {
+	aesKey, err := encryption.NewAESKey(256)
+	require.NoError(t, err)
+	resolver := &testKeyResolver{key: aesKey}
+
+	cipher := encryption.NewAESGCMCipher()
+	encrypted, err := cipher.Encrypt([]byte("secret message"), resolver)
+	require.NoError(t, err)
+	decrypted, err := cipher.Decrypt(encrypted, resolver)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret message"), decrypted)
+
+	// the key is resolved for every operation and released afterwards
+	assert.Equal(t, 2, resolver.resolved)
+	assert.Equal(t, 2, resolver.released)
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption
+
+import (
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// AESGCMSegmentedMaxSegmentSizeBytes is the largest supported segment size. It is the maximum number of bytes that
+	// can be safely encrypted using a single AES-GCM key and nonce (2^39-256 bits).
+	AESGCMSegmentedMaxSegmentSizeBytes = 1<<36 - 32
+
+	aesGCMSegmentedSaltSizeBytes = 32
+	aesGCMSegmentedInfoPrefix    = "encrypted-config-value AES-GCM segment"
+)
+
+// AESGCMSegmentedCipher is a cipher that supports encrypting and decrypting values of arbitrary size using AES keys.
+// The plaintext is split into segments of a fixed size and every segment is encrypted using AES-GCM with a key and
+// nonce that are unique to the segment. The key and nonce for a segment are derived from the AES key, a random salt
+// and the index of the segment using HKDF-SHA-256, so no single key and nonce pair is ever used to encrypt more than
+// the segment size. The index of the segment and whether or not it is the final segment are authenticated, so segments
+// cannot be reordered, dropped or truncated without detection.
+type AESGCMSegmentedCipher struct {
+	segmentSizeBytes int
+}
+
+// AESGCMSegmentedCipherWithSegmentSize returns a new AESGCMSegmentedCipher that uses the specified segment size (in
+// bytes). Returns an error if the segment size is not positive or is larger than AESGCMSegmentedMaxSegmentSizeBytes.
+func AESGCMSegmentedCipherWithSegmentSize(segmentSizeBytes int) (*AESGCMSegmentedCipher, error) {
+	if segmentSizeBytes <= 0 || int64(segmentSizeBytes) > AESGCMSegmentedMaxSegmentSizeBytes {
+		return nil, fmt.Errorf("segment size must be between 1 and %d bytes, was %d", int64(AESGCMSegmentedMaxSegmentSizeBytes), segmentSizeBytes)
+	}
+	return &AESGCMSegmentedCipher{
+		segmentSizeBytes: segmentSizeBytes,
+	}, nil
+}
+
+// SegmentSize returns the size (in bytes) of the plaintext of each segment for this cipher.
+func (a *AESGCMSegmentedCipher) SegmentSize() int {
+	return a.segmentSizeBytes
+}
+
+// Encrypt encrypts the provided value using the specified key. The key must be of type *AESKey. The returned bytes are
+// [salt+segment_0+...+segment_n], where every segment is [ciphertext+tag].
+func (a *AESGCMSegmentedCipher) Encrypt(data []byte, key Key) ([]byte, error) {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	aesKey, ok := key.(*AESKey)
+	if !ok {
+		return nil, fmt.Errorf("key must be of *AESKey, but was %T", key)
+	}
+	salt, err := RandomBytes(aesGCMSegmentedSaltSizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %v", err)
+	}
+
+	out := append([]byte{}, salt...)
+	for index := uint64(0); ; index++ {
+		segment := data
+		if len(segment) > a.segmentSizeBytes {
+			segment = segment[:a.segmentSizeBytes]
+		}
+		data = data[len(segment):]
+		last := len(data) == 0
+
+		gcm, nonce, err := a.segmentCipher(aesKey, salt, index)
+		if err != nil {
+			return nil, err
+		}
+		out = gcm.Seal(out, nonce, segment, segmentAdditionalData(index, last))
+		if last {
+			return out, nil
+		}
+	}
+}
+
+// Parts takes output bytes of the form generated by Encrypt and splits them into the salt and the encrypted segments.
+func (a *AESGCMSegmentedCipher) Parts(encryptedData []byte) (salt []byte, segments []byte) {
+	return encryptedData[:aesGCMSegmentedSaltSizeBytes], encryptedData[aesGCMSegmentedSaltSizeBytes:]
+}
+
+// Decrypt decrypts the provided value using the specified key. The key must be of type *AESKey. The provided data must
+// be of the form generated by Encrypt. Returns the bytes for the decrypted ciphertext (the input originally provided to
+// Encrypt).
+func (a *AESGCMSegmentedCipher) Decrypt(data []byte, key Key) ([]byte, error) {
+	var decrypted []byte
+	if err := a.DecryptSegments(data, key, func(segment []byte) error {
+		decrypted = append(decrypted, segment...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// DecryptSegments decrypts the provided value using the specified key and calls the provided function with the
+// plaintext of each segment in order. The plaintext of a segment is only provided after the segment has been
+// authenticated. The key must be of type *AESKey. Returns an error if any segment fails to decrypt or if the provided
+// function returns an error.
+func (a *AESGCMSegmentedCipher) DecryptSegments(data []byte, key Key, emit func(segment []byte) error) error {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return err
+	}
+	defer release()
+	aesKey, ok := key.(*AESKey)
+	if !ok {
+		return fmt.Errorf("key must be of type *AESKey, was %T", key)
+	}
+	if len(data) < aesGCMSegmentedSaltSizeBytes+aesGCMDefaultTagSizeBytes {
+		return fmt.Errorf("failed to decrypt value: ciphertext too short")
+	}
+	salt, segments := a.Parts(data)
+
+	encryptedSegmentSize := a.segmentSizeBytes + aesGCMDefaultTagSizeBytes
+	for index := uint64(0); ; index++ {
+		segment := segments
+		if len(segment) > encryptedSegmentSize {
+			segment = segment[:encryptedSegmentSize]
+		}
+		segments = segments[len(segment):]
+		last := len(segments) == 0
+
+		gcm, nonce, err := a.segmentCipher(aesKey, salt, index)
+		if err != nil {
+			return err
+		}
+		plain, err := gcm.Open(nil, nonce, segment, segmentAdditionalData(index, last))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt segment %d: %v", index, err)
+		}
+		if err := emit(plain); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// segmentCipher returns the AES-GCM cipher and nonce for the segment with the provided index.
+func (a *AESGCMSegmentedCipher) segmentCipher(key *AESKey, salt []byte, index uint64) (cipher.AEAD, []byte, error) {
+	info := make([]byte, len(aesGCMSegmentedInfoPrefix)+8)
+	copy(info, aesGCMSegmentedInfoPrefix)
+	binary.BigEndian.PutUint64(info[len(aesGCMSegmentedInfoPrefix):], index)
+
+	derived, err := HKDF(sha256.New, key.key, salt, info, len(key.key)+aesGCMDefaultNonceSizeBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := newBlockCipher(AESKeyFromBytes(derived[:len(key.key)]), aesGCMDefaultNonceSizeBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, derived[len(key.key):], nil
+}
+
+// segmentAdditionalData returns the additional authenticated data for a segment, which is [index+last], where index is
+// a big-endian uint64 and last is 1 for the final segment and 0 otherwise.
+func segmentAdditionalData(index uint64, last bool) []byte {
+	aad := make([]byte, 9)
+	binary.BigEndian.PutUint64(aad, index)
+	if last {
+		aad[8] = 1
+	}
+	return aad
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCMSegmentedEncryptDecrypt(t *testing.T) {
+	for i, currCase := range []struct {
+		name         string
+		segmentSize  int
+		input        []byte
+		wantSegments int
+	}{
+		{"empty input", 16, []byte{}, 1},
+		{"input smaller than segment", 16, []byte("secret"), 1},
+		{"input exactly one segment", 6, []byte("secret"), 1},
+		{"input multiple of segment size", 3, []byte("secret"), 2},
+		{"input spanning partial segment", 4, []byte("secret message"), 4},
+	} {
+		aesKey, err := encryption.NewAESKey(256)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		cipher, err := encryption.AESGCMSegmentedCipherWithSegmentSize(currCase.segmentSize)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		encrypted, err := cipher.Encrypt(currCase.input, aesKey)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		var segments [][]byte
+		err = cipher.DecryptSegments(encrypted, aesKey, func(segment []byte) error {
+			segments = append(segments, segment)
+			return nil
+		})
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Len(t, segments, currCase.wantSegments, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, string(currCase.input), string(bytes.Join(segments, nil)), "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestAESGCMSegmentedTampering(t *testing.T) {
+	aesKey, err := encryption.NewAESKey(256)
+	require.NoError(t, err)
+	cipher, err := encryption.AESGCMSegmentedCipherWithSegmentSize(4)
+	require.NoError(t, err)
+	encrypted, err := cipher.Encrypt([]byte("secret message"), aesKey)
+	require.NoError(t, err)
+	const encryptedSegmentSize = 4 + 16
+
+	salt, segments := cipher.Parts(encrypted)
+	for i, currCase := range []struct {
+		name     string
+		tampered []byte
+	}{
+		{"truncated after a full segment", append(append([]byte{}, salt...), segments[:2*encryptedSegmentSize]...)},
+		{"segments swapped", append(append(append(append([]byte{}, salt...), segments[encryptedSegmentSize:2*encryptedSegmentSize]...), segments[:encryptedSegmentSize]...), segments[2*encryptedSegmentSize:]...)},
+		{"bit flipped", append(append([]byte{}, encrypted[:len(encrypted)-1]...), encrypted[len(encrypted)-1]^1)},
+	} {
+		_, err := cipher.Decrypt(currCase.tampered, aesKey)
+		assert.Error(t, err, "Case %d: %s", i, currCase.name)
+	}
+}
+
+func TestAESGCMSegmentedInvalidSegmentSize(t *testing.T) {
+	_, err := encryption.AESGCMSegmentedCipherWithSegmentSize(0)
+	assert.EqualError(t, err, "segment size must be between 1 and 68719476704 bytes, was 0")
+}
@@ -28,3 +28,25 @@ type Key interface {
 	// the exact format of the returned bytes.
 	Bytes() []byte
 }
+
+// KeyResolver is implemented by Key implementations that do not hold their key material but load it every time they
+// are used, such as keys that are stored in an external key store. The ciphers in this package call ResolveKey at the
+// start of every operation, use the returned key for the operation and call the returned release function once the
+// operation is complete.
+type KeyResolver interface {
+	Key
+
+	// ResolveKey loads the key material and returns a key that holds it, along with a function that releases the
+	// returned key (for example, by zeroing its key material). The returned key must not be a KeyResolver.
+	ResolveKey() (key Key, release func(), err error)
+}
+
+// resolveKey returns the key that should be used for an operation that uses the provided key: the result of
+// ResolveKey if the provided key is a KeyResolver, and the provided key otherwise.
+func resolveKey(key Key) (Key, func(), error) {
+	resolver, ok := key.(KeyResolver)
+	if !ok {
+		return key, func() {}, nil
+	}
+	return resolver.ResolveKey()
+}
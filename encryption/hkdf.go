@@ -0,0 +1,43 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"hash"
+)
+
+// HKDF derives length bytes of key material from the provided input key material, salt and info using the HMAC-based
+// key derivation function defined in RFC 5869 with the provided hash function. Returns an error if length is larger
+// than the maximum output length of HKDF for the hash function (255 times the size of the hash).
+func HKDF(newHash func() hash.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	hashSize := newHash().Size()
+	if length < 0 || length > 255*hashSize {
+		return nil, fmt.Errorf("HKDF output length must be between 0 and %d bytes, was %d", 255*hashSize, length)
+	}
+	if salt == nil {
+		salt = make([]byte, hashSize)
+	}
+
+	// extract
+	extractor := hmac.New(newHash, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+
+	// expand
+	expander := hmac.New(newHash, prk)
+	out := make([]byte, 0, length+hashSize)
+	var prev []byte
+	for counter := byte(1); len(out) < length; counter++ {
+		expander.Reset()
+		expander.Write(prev)
+		expander.Write(info)
+		expander.Write([]byte{counter})
+		prev = expander.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length], nil
+}
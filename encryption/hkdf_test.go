@@ -0,0 +1,55 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHKDF(t *testing.T) {
+	// test cases from RFC 5869 Appendix A
+	for i, currCase := range []struct {
+		name   string
+		ikm    string
+		salt   string
+		info   string
+		length int
+		want   string
+	}{
+		{
+			name:   "basic test case with SHA-256",
+			ikm:    "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			salt:   "000102030405060708090a0b0c",
+			info:   "f0f1f2f3f4f5f6f7f8f9",
+			length: 42,
+			want:   "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+		},
+		{
+			name:   "SHA-256 with zero-length salt/info",
+			ikm:    "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+			salt:   "",
+			info:   "",
+			length: 42,
+			want:   "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+		},
+	} {
+		ikm, err := hex.DecodeString(currCase.ikm)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		salt, err := hex.DecodeString(currCase.salt)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		info, err := hex.DecodeString(currCase.info)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+
+		got, err := encryption.HKDF(sha256.New, ikm, salt, info, currCase.length)
+		require.NoError(t, err, "Case %d: %s", i, currCase.name)
+		assert.Equal(t, currCase.want, hex.EncodeToString(got), "Case %d: %s", i, currCase.name)
+	}
+}
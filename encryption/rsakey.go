@@ -101,6 +101,20 @@ func RSAPrivateKeyFromPKCS8Bytes(key []byte) (*RSAPrivateKey, error) {
 	return rsaPrivateKeyFromKey(rsaPrivKey), nil
 }
 
+// RSAPrivateKeyFromKey returns a new RSA private key that wraps the provided key. If the CRT values of the provided key
+// have not been precomputed, they are computed (and stored in the provided key) so that decryption operations using
+// the returned key are faster. The provided key must not be used concurrently with this call.
+func RSAPrivateKeyFromKey(rsaPrivKey *rsa.PrivateKey) *RSAPrivateKey {
+	return rsaPrivateKeyFromKey(rsaPrivKey)
+}
+
+// rsaPrivateKeyFromKey returns the provided key as an RSAPrivateKey after precomputing its CRT values if they have not
+// already been computed. Keys returned by rsa.GenerateKey and x509.ParsePKCS8PrivateKey are already precomputed, so
+// Precompute is called at most once for every key. The values are computed when the key is constructed rather than
+// when it is first used because decryption operations may use the key concurrently.
 func rsaPrivateKeyFromKey(rsaPrivKey *rsa.PrivateKey) *RSAPrivateKey {
+	if rsaPrivKey.Precomputed.Dp == nil {
+		rsaPrivKey.Precompute()
+	}
 	return (*RSAPrivateKey)(rsaPrivKey)
 }
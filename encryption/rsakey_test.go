@@ -5,6 +5,7 @@
 package encryption_test
 
 import (
+	"crypto/rsa"
 	"testing"
 
 	"github.com/palantir/go-encrypted-config-value/encryption"
@@ -34,3 +35,57 @@ func TestRSAKeySerDe(t *testing.T) {
 
 	assert.Equal(t, plaintext, string(decrypted))
 }
+
+func TestRSAPrivateKeyFromKeyPrecomputes(t *testing.T) {
+	_, privKey, err := encryption.NewRSAKeyPair(2048)
+	require.NoError(t, err)
+
+	rsaPrivKey := withoutPrecomputedValues((*rsa.PrivateKey)(privKey))
+	require.Nil(t, rsaPrivKey.Precomputed.Dp)
+	key := encryption.RSAPrivateKeyFromKey(rsaPrivKey)
+	assert.NotNil(t, key.Precomputed.Dp)
+
+	parsed, err := encryption.RSAPrivateKeyFromPKCS8Bytes(privKey.Bytes())
+	require.NoError(t, err)
+	assert.NotNil(t, parsed.Precomputed.Dp)
+}
+
+func BenchmarkRSADecrypt(b *testing.B) {
+	pubKey, privKey, err := encryption.NewRSAKeyPair(2048)
+	require.NoError(b, err)
+	cipher := encryption.NewRSAOAEPCipher()
+	encrypted, err := cipher.Encrypt([]byte("input plaintext"), pubKey)
+	require.NoError(b, err)
+
+	for _, currCase := range []struct {
+		name string
+		key  *encryption.RSAPrivateKey
+	}{
+		{
+			name: "precomputed",
+			key:  privKey,
+		},
+		{
+			// key is cast directly rather than using RSAPrivateKeyFromKey, so its values are not precomputed
+			name: "not precomputed",
+			key:  (*encryption.RSAPrivateKey)(withoutPrecomputedValues((*rsa.PrivateKey)(privKey))),
+		},
+	} {
+		b.Run(currCase.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := cipher.Decrypt(encrypted, currCase.key); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func withoutPrecomputedValues(key *rsa.PrivateKey) *rsa.PrivateKey {
+	return &rsa.PrivateKey{
+		PublicKey: key.PublicKey,
+		D:         key.D,
+		Primes:    key.Primes,
+	}
+}
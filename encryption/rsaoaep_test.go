@@ -34,3 +34,21 @@ func TestRSAEncryptDecrypt(t *testing.T) {
 		assert.Equal(t, currCase.input, decrypted, "Case %d", i)
 	}
 }
+
+func TestRSAEncryptDecryptWithLabel(t *testing.T) {
+	pubKey, privKey, err := encryption.NewRSAKeyPair(2048)
+	require.NoError(t, err)
+
+	cipher := encryption.RSAOAEPCipherWithAlgorithms(encryption.SHA256, encryption.SHA256)
+	encrypted, err := cipher.EncryptWithLabel([]byte("secret message"), []byte("context"), pubKey)
+	require.NoError(t, err)
+
+	decrypted, err := cipher.DecryptWithLabel(encrypted, []byte("context"), privKey)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret message"), decrypted)
+
+	_, err = cipher.DecryptWithLabel(encrypted, []byte("other"), privKey)
+	assert.Error(t, err)
+	_, err = cipher.Decrypt(encrypted, privKey)
+	assert.Error(t, err)
+}
@@ -70,11 +70,23 @@ func RSAOAEPCipherWithAlgorithms(oaepHashAlg, mdf1HashAlg HashAlgorithm) *RSAOAE
 // Encrypt encrypts the provided value using the specified key. The key must be of type *RSAPublicKey. The returned
 // bytes are the encrypted ciphertext.
 func (r *RSAOAEPCipher) Encrypt(data []byte, key Key) ([]byte, error) {
+	return r.EncryptWithLabel(data, nil, key)
+}
+
+// EncryptWithLabel behaves like Encrypt, but uses the provided OAEP label. The label is authenticated but is not
+// encrypted and is not included in the output. The same label must be provided to DecryptWithLabel to decrypt the
+// output. Encrypt is equivalent to EncryptWithLabel using an empty label.
+func (r *RSAOAEPCipher) EncryptWithLabel(data, label []byte, key Key) ([]byte, error) {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	pubKey, ok := key.(*RSAPublicKey)
 	if !ok {
 		return nil, fmt.Errorf("key must be of *RSAPublicKey, but was %T", key)
 	}
-	encrypted, err := encryptOAEP(r.oaepHashAlg.Hash(), r.mdf1HashAlg.Hash(), rand.Reader, (*rsa.PublicKey)(pubKey), data, []byte{})
+	encrypted, err := encryptOAEP(r.oaepHashAlg.Hash(), r.mdf1HashAlg.Hash(), rand.Reader, (*rsa.PublicKey)(pubKey), data, label)
 	if err != nil {
 		return nil, err
 	}
@@ -83,11 +95,22 @@ func (r *RSAOAEPCipher) Encrypt(data []byte, key Key) ([]byte, error) {
 
 // Decrypt decrypts the provided value using the specified key. The key must be of type *RSAPrivateKey.
 func (r *RSAOAEPCipher) Decrypt(data []byte, key Key) ([]byte, error) {
+	return r.DecryptWithLabel(data, nil, key)
+}
+
+// DecryptWithLabel behaves like Decrypt, but uses the provided OAEP label, which must be the label that was provided
+// to EncryptWithLabel.
+func (r *RSAOAEPCipher) DecryptWithLabel(data, label []byte, key Key) ([]byte, error) {
+	key, release, err := resolveKey(key)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 	privKey, ok := key.(*RSAPrivateKey)
 	if !ok {
 		return nil, fmt.Errorf("key must be of type *RSAPrivateKey, was %T", key)
 	}
-	decrypted, err := decryptOAEP(r.oaepHashAlg.Hash(), r.mdf1HashAlg.Hash(), rand.Reader, (*rsa.PrivateKey)(privKey), data, []byte{})
+	decrypted, err := decryptOAEP(r.oaepHashAlg.Hash(), r.mdf1HashAlg.Hash(), rand.Reader, (*rsa.PrivateKey)(privKey), data, label)
 	if err != nil {
 		return nil, err
 	}
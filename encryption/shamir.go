@@ -0,0 +1,121 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption
+
+import (
+	"fmt"
+)
+
+// MaxSecretShares is the maximum number of shares that a secret can be split into using SplitSecret.
+const MaxSecretShares = 255
+
+// SplitSecret splits the provided secret into the specified number of shares using Shamir's secret sharing over
+// GF(2^8) such that any threshold of the shares can be used to reconstruct the secret using CombineShares, while fewer
+// than threshold shares reveal no information about the secret. Every returned share is [x+y], where x is the non-zero
+// x-coordinate of the share and y has the same length as the secret. Returns an error if threshold is not between 1
+// and shares or if shares is larger than MaxSecretShares.
+func SplitSecret(secret []byte, shares, threshold int) ([][]byte, error) {
+	if shares < 1 || shares > MaxSecretShares {
+		return nil, fmt.Errorf("number of shares must be between 1 and %d, was %d", MaxSecretShares, shares)
+	}
+	if threshold < 1 || threshold > shares {
+		return nil, fmt.Errorf("threshold must be between 1 and the number of shares (%d), was %d", shares, threshold)
+	}
+
+	out := make([][]byte, shares)
+	for i := range out {
+		out[i] = make([]byte, len(secret)+1)
+		out[i][0] = byte(i + 1)
+	}
+	// coefficients[0] is the secret byte and the remaining coefficients are random
+	coefficients := make([]byte, threshold)
+	for byteIdx, secretByte := range secret {
+		random, err := RandomBytes(threshold - 1)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[0] = secretByte
+		copy(coefficients[1:], random)
+		for _, share := range out {
+			share[byteIdx+1] = gf256EvalPolynomial(coefficients, share[0])
+		}
+	}
+	zero(coefficients)
+	return out, nil
+}
+
+// CombineShares reconstructs a secret from shares returned by SplitSecret. At least the threshold number of shares
+// that was provided to SplitSecret must be provided: if fewer shares are provided, the returned value will not be the
+// secret. Returns an error if the shares are malformed, have different lengths or have duplicate x-coordinates.
+func CombineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("at least one share must be provided")
+	}
+	shareLen := len(shares[0])
+	seen := make(map[byte]bool)
+	for _, share := range shares {
+		if len(share) < 2 || len(share) != shareLen {
+			return nil, fmt.Errorf("shares must all have the same length and contain at least 2 bytes")
+		}
+		if share[0] == 0 || seen[share[0]] {
+			return nil, fmt.Errorf("shares must have distinct non-zero x-coordinates")
+		}
+		seen[share[0]] = true
+	}
+
+	secret := make([]byte, shareLen-1)
+	for i, share := range shares {
+		// Lagrange basis polynomial for share i evaluated at x = 0: prod_{j != i} x_j / (x_j - x_i), where subtraction
+		// in GF(2^8) is XOR.
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			basis = gf256Mul(basis, gf256Mul(other[0], gf256Inverse(other[0]^share[0])))
+		}
+		for byteIdx := range secret {
+			secret[byteIdx] ^= gf256Mul(share[byteIdx+1], basis)
+		}
+	}
+	return secret, nil
+}
+
+// gf256EvalPolynomial evaluates the polynomial with the provided coefficients (lowest degree first) at x using Horner's
+// method.
+func gf256EvalPolynomial(coefficients []byte, x byte) byte {
+	var out byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		out = gf256Mul(out, x) ^ coefficients[i]
+	}
+	return out
+}
+
+// gf256Mul multiplies a and b in GF(2^8) using the AES reduction polynomial x^8 + x^4 + x^3 + x + 1. The number of
+// operations does not depend on the inputs.
+func gf256Mul(a, b byte) byte {
+	var out byte
+	for i := 0; i < 8; i++ {
+		out ^= a & -(b & 1)
+		b >>= 1
+		a = a<<1 ^ 0x1b&-(a>>7)
+	}
+	return out
+}
+
+// gf256Inverse returns the multiplicative inverse of a in GF(2^8), which is a^254. Returns 0 if a is 0.
+func gf256Inverse(a byte) byte {
+	out := byte(1)
+	for i := 0; i < 254; i++ {
+		out = gf256Mul(out, a)
+	}
+	return out
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Palantir Technologies. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package encryption_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-encrypted-config-value/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAndCombineShares(t *testing.T) {
+	secret, err := encryption.RandomBytes(32)
+	require.NoError(t, err)
+
+	for i, currCase := range []struct {
+		shares    int
+		threshold int
+	}{
+		{1, 1},
+		{3, 1},
+		{3, 2},
+		{3, 3},
+		{5, 3},
+	} {
+		shares, err := encryption.SplitSecret(secret, currCase.shares, currCase.threshold)
+		require.NoError(t, err, "Case %d", i)
+		require.Len(t, shares, currCase.shares, "Case %d", i)
+
+		// every subset of exactly threshold shares reconstructs the secret
+		for _, subset := range subsetsOfSize(shares, currCase.threshold) {
+			combined, err := encryption.CombineShares(subset)
+			require.NoError(t, err, "Case %d", i)
+			assert.Equal(t, secret, combined, "Case %d", i)
+		}
+		// fewer than threshold shares do not reconstruct the secret
+		if currCase.threshold > 1 {
+			combined, err := encryption.CombineShares(shares[:currCase.threshold-1])
+			require.NoError(t, err, "Case %d", i)
+			assert.NotEqual(t, secret, combined, "Case %d", i)
+		}
+	}
+}
+
+func TestSplitSecretErrors(t *testing.T) {
+	for i, currCase := range []struct {
+		shares    int
+		threshold int
+		wantErr   string
+	}{
+		{0, 1, "number of shares must be between 1 and 255, was 0"},
+		{256, 1, "number of shares must be between 1 and 255, was 256"},
+		{3, 0, "threshold must be between 1 and the number of shares (3), was 0"},
+		{3, 4, "threshold must be between 1 and the number of shares (3), was 4"},
+	} {
+		_, err := encryption.SplitSecret([]byte("secret"), currCase.shares, currCase.threshold)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d", i)
+	}
+}
+
+func TestCombineSharesErrors(t *testing.T) {
+	for i, currCase := range []struct {
+		shares  [][]byte
+		wantErr string
+	}{
+		{nil, "at least one share must be provided"},
+		{[][]byte{{1, 2}, {2, 3, 4}}, "shares must all have the same length and contain at least 2 bytes"},
+		{[][]byte{{1, 2}, {1, 3}}, "shares must have distinct non-zero x-coordinates"},
+		{[][]byte{{0, 2}}, "shares must have distinct non-zero x-coordinates"},
+	} {
+		_, err := encryption.CombineShares(currCase.shares)
+		assert.EqualError(t, err, currCase.wantErr, "Case %d", i)
+	}
+}
+
+func subsetsOfSize(shares [][]byte, size int) [][][]byte {
+	if size == 0 {
+		return [][][]byte{nil}
+	}
+	var out [][][]byte
+	for i := 0; i <= len(shares)-size; i++ {
+		for _, rest := range subsetsOfSize(shares[i+1:], size-1) {
+			out = append(out, append([][]byte{shares[i]}, rest...))
+		}
+	}
+	return out
+}